@@ -19,6 +19,12 @@ var DefaultConfig []byte
 
 // Program entry point.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "battery-report" {
+		batteryReport()
+
+		return
+	}
+
 	Conf, err := lib.ReadConf(DefaultConfig)
 
 	if err != nil {
@@ -27,6 +33,8 @@ func main() {
 
 	Conf.Channels.UpdateReady = make(chan bool)
 	Conf.Channels.SVPAHandlerChan = make(chan lib.ClickEvent, 256)
+	Conf.Channels.MicHandlerChan = make(chan lib.ClickEvent, 256)
+	Conf.Channels.StreamHandlerChan = make(chan lib.ClickEvent, 256)
 	Conf.Channels.MsgChan = make(chan []lib.I3BarOutBlock, 64)
 	Conf.Channels.SigChan = make(chan os.Signal, 1)
 	Conf.Channels.RunChan = make(chan []string, 128)
@@ -35,12 +43,16 @@ func main() {
 	Conf.Values.ClockTime = "Thu, 1 Jan 1970   1:00"
 	Conf.Values.IfStatus = ""
 	Conf.Values.VPNStatus = ""
-	Conf.Values.Memory.Shared = 0
-	Conf.Values.Memory.Usedpct = 0
-	Conf.Values.Memory.Swap = 0
-	Conf.Values.La = "-1"
+	Conf.Values.Memory = ""
+	Conf.Values.La = "?"
+	Conf.Values.LaColor = Conf.LA.Color
 	// Conf.Values.PA
 	Conf.Values.SoundVolume = "🔊:0%"
+	Conf.Values.BindingMode = "default"
+	Conf.Values.Layout = "?"
+	Conf.Values.FocusTime = Conf.FocusTime.Symbol
+	Conf.Values.MicVolume = Conf.MicVolumePa.Symbol + ":?%"
+	Conf.Values.CPUUsagePerCore = Conf.CPUUsage.PerCore
 
 	// TODO: Проставить дефолтные значения для глобальных переменных модулей.
 	Conf.Values.BatteryString = fmt.Sprintf(
@@ -98,7 +110,7 @@ func main() {
 	go Conf.SVPAHandler()
 	go PrintToI3bar(Conf)
 
-	if Conf.AppButtons.Enabled {
+	if Conf.AppButtons.Enabled || Conf.WindowTitle.Enabled || Conf.FocusTime.Enabled {
 		go Conf.UpdateI3WinList()
 	}
 
@@ -155,6 +167,48 @@ func main() {
 		go Conf.RunCommand()
 	}
 
+	if Conf.BindingMode.Enabled {
+		go Conf.UpdateBindingMode()
+	}
+
+	if Conf.Layout.Enabled {
+		go Conf.UpdateLayout()
+	}
+
+	if Conf.FocusTime.Enabled {
+		go Conf.UpdateFocusTime()
+	}
+
+	if Conf.MicVolumePa.Enabled {
+		go Conf.UpdateMicVolume()
+		go Conf.MicHandler()
+	}
+
+	if Conf.StreamVolumePa.Enabled {
+		go Conf.UpdateStreamVolume()
+		go Conf.StreamHandler()
+	}
+
+	if Conf.CardProfile.Enabled {
+		go Conf.UpdateCardProfile()
+	}
+
+	if Conf.Sensors.Enabled {
+		go Conf.UpdateSensors()
+	}
+
+	if Conf.CPUUsage.Enabled {
+		go Conf.UpdateCPUUsage()
+	}
+
+	if Conf.CPUFreq.Enabled {
+		go Conf.UpdateCPUFreq()
+	}
+
+	if Conf.PSI.Enabled {
+		go Conf.UpdatePSI()
+	}
+
 	/*
 		I3bar documentation pretends that message protocol must be valid json. In practice, we only have to print valid
 		header, empty json array and (potentially infinite) json lines (line that is valid json by itself) that is
@@ -233,6 +287,484 @@ func main() {
 			}
 		}
 
+		// Binding mode block is shown only when some non-default mode is active.
+		if Conf.BindingMode.Enabled && Conf.Values.BindingMode != "default" && Conf.Values.BindingMode != "" {
+			var b lib.I3BarOutBlock
+
+			color, background := Conf.BindingModeColors(Conf.Values.BindingMode)
+
+			b.Name = `binding-mode`
+			b.Color = color
+			b.Background = background
+			b.Urgent = Conf.BindingMode.Urgent
+
+			if Conf.BindingMode.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.BindingMode.Separator.Left.Color,
+					Conf.BindingMode.Separator.Left.Background,
+					Conf.BindingMode.Separator.Left.Font,
+					Conf.BindingMode.Separator.Left.FontSize,
+					Conf.BindingMode.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				color,
+				background,
+				Conf.BindingMode.Font,
+				Conf.BindingMode.FontSize,
+				Conf.Values.BindingMode,
+			)
+
+			if Conf.BindingMode.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.BindingMode.Separator.Right.Color,
+					Conf.BindingMode.Separator.Right.Background,
+					Conf.BindingMode.Separator.Right.Font,
+					Conf.BindingMode.Separator.Right.FontSize,
+					Conf.BindingMode.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		if Conf.WindowTitle.Enabled && Conf.Values.WindowTitle != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = `window-title`
+			b.Color = Conf.WindowTitle.Color
+			b.Background = Conf.WindowTitle.Background
+
+			if Conf.WindowTitle.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.WindowTitle.Separator.Left.Color,
+					Conf.WindowTitle.Separator.Left.Background,
+					Conf.WindowTitle.Separator.Left.Font,
+					Conf.WindowTitle.Separator.Left.FontSize,
+					Conf.WindowTitle.Separator.Left.Symbol,
+				)
+			}
+
+			// Title is already escaped in plugin src.
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.WindowTitle.Color,
+				Conf.WindowTitle.Background,
+				Conf.WindowTitle.Font,
+				Conf.WindowTitle.FontSize,
+				Conf.Values.WindowTitle,
+			)
+
+			if Conf.WindowTitle.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.WindowTitle.Separator.Right.Color,
+					Conf.WindowTitle.Separator.Right.Background,
+					Conf.WindowTitle.Separator.Right.Font,
+					Conf.WindowTitle.Separator.Right.FontSize,
+					Conf.WindowTitle.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		if Conf.Layout.Enabled {
+			var b lib.I3BarOutBlock
+
+			b.Name = `layout`
+			b.Color = Conf.Layout.Color
+			b.Background = Conf.Layout.Background
+
+			if Conf.Layout.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.Layout.Separator.Left.Color,
+					Conf.Layout.Separator.Left.Background,
+					Conf.Layout.Separator.Left.Font,
+					Conf.Layout.Separator.Left.FontSize,
+					Conf.Layout.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.Layout.Color,
+				Conf.Layout.Background,
+				Conf.Layout.Font,
+				Conf.Layout.FontSize,
+				Conf.Values.Layout,
+			)
+
+			if Conf.Layout.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.Layout.Separator.Right.Color,
+					Conf.Layout.Separator.Right.Background,
+					Conf.Layout.Separator.Right.Font,
+					Conf.Layout.Separator.Right.FontSize,
+					Conf.Layout.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		if Conf.FocusTime.Enabled {
+			var b lib.I3BarOutBlock
+
+			b.Name = `focus-time`
+			b.Color = Conf.FocusTime.Color
+			b.Background = Conf.FocusTime.Background
+
+			if Conf.FocusTime.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.FocusTime.Separator.Left.Color,
+					Conf.FocusTime.Separator.Left.Background,
+					Conf.FocusTime.Separator.Left.Font,
+					Conf.FocusTime.Separator.Left.FontSize,
+					Conf.FocusTime.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.FocusTime.Color,
+				Conf.FocusTime.Background,
+				Conf.FocusTime.Font,
+				Conf.FocusTime.FontSize,
+				Conf.Values.FocusTime,
+			)
+
+			if Conf.FocusTime.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.FocusTime.Separator.Right.Color,
+					Conf.FocusTime.Separator.Right.Background,
+					Conf.FocusTime.Separator.Right.Font,
+					Conf.FocusTime.Separator.Right.FontSize,
+					Conf.FocusTime.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		if Conf.MicVolumePa.Enabled {
+			var b lib.I3BarOutBlock
+
+			b.Name = "mic-volume-pa"
+			b.Color = Conf.MicVolumePa.Color
+			b.Background = Conf.MicVolumePa.Background
+			b.Urgent = Conf.MicVolumePa.InUseUrgent && Conf.Values.MicInUse
+
+			if Conf.MicVolumePa.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.MicVolumePa.Separator.Left.Color,
+					Conf.MicVolumePa.Separator.Left.Background,
+					Conf.MicVolumePa.Separator.Left.Font,
+					Conf.MicVolumePa.Separator.Left.FontSize,
+					Conf.MicVolumePa.Separator.Left.Symbol,
+				)
+			}
+
+			// Pango format is already applied in plugin src.
+			b.FullText += Conf.Values.MicVolume
+
+			if Conf.MicVolumePa.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.MicVolumePa.Separator.Right.Color,
+					Conf.MicVolumePa.Separator.Right.Background,
+					Conf.MicVolumePa.Separator.Right.Font,
+					Conf.MicVolumePa.Separator.Right.FontSize,
+					Conf.MicVolumePa.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		// Stream volume block is empty if there are no streams and hide_idle is set.
+		if Conf.StreamVolumePa.Enabled && Conf.Values.StreamVolume != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "stream-volume-pa"
+			b.Color = Conf.StreamVolumePa.Color
+			b.Background = Conf.StreamVolumePa.Background
+
+			if Conf.StreamVolumePa.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.StreamVolumePa.Separator.Left.Color,
+					Conf.StreamVolumePa.Separator.Left.Background,
+					Conf.StreamVolumePa.Separator.Left.Font,
+					Conf.StreamVolumePa.Separator.Left.FontSize,
+					Conf.StreamVolumePa.Separator.Left.Symbol,
+				)
+			}
+
+			// Pango format is already applied in plugin src.
+			b.FullText += Conf.Values.StreamVolume
+
+			if Conf.StreamVolumePa.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.StreamVolumePa.Separator.Right.Color,
+					Conf.StreamVolumePa.Separator.Right.Background,
+					Conf.StreamVolumePa.Separator.Right.Font,
+					Conf.StreamVolumePa.Separator.Right.FontSize,
+					Conf.StreamVolumePa.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		// Card profile block is empty if card is missing and hide_missing is set.
+		if Conf.CardProfile.Enabled && Conf.Values.CardProfile != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "card-profile"
+			b.Color = Conf.CardProfile.Color
+			b.Background = Conf.CardProfile.Background
+
+			if Conf.CardProfile.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CardProfile.Separator.Left.Color,
+					Conf.CardProfile.Separator.Left.Background,
+					Conf.CardProfile.Separator.Left.Font,
+					Conf.CardProfile.Separator.Left.FontSize,
+					Conf.CardProfile.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.CardProfile.Color,
+				Conf.CardProfile.Background,
+				Conf.CardProfile.Font,
+				Conf.CardProfile.FontSize,
+				Conf.Values.CardProfile,
+			)
+
+			if Conf.CardProfile.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CardProfile.Separator.Right.Color,
+					Conf.CardProfile.Separator.Right.Background,
+					Conf.CardProfile.Separator.Right.Font,
+					Conf.CardProfile.Separator.Right.FontSize,
+					Conf.CardProfile.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		// Sensors block is empty until at least one sensor is read.
+		if Conf.Sensors.Enabled && Conf.Values.Sensors != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "sensors"
+			b.Color = Conf.Sensors.Color
+			b.Background = Conf.Sensors.Background
+			b.Urgent = Conf.Values.SensorsUrgent
+
+			if Conf.Sensors.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.Sensors.Separator.Left.Color,
+					Conf.Sensors.Separator.Left.Background,
+					Conf.Sensors.Separator.Left.Font,
+					Conf.Sensors.Separator.Left.FontSize,
+					Conf.Sensors.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += Conf.Values.Sensors
+
+			if Conf.Sensors.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.Sensors.Separator.Right.Color,
+					Conf.Sensors.Separator.Right.Background,
+					Conf.Sensors.Separator.Right.Font,
+					Conf.Sensors.Separator.Right.FontSize,
+					Conf.Sensors.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		// Cpu usage block is empty until second reading of /proc/stat.
+		if Conf.CPUUsage.Enabled && Conf.Values.CPUUsage != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "cpu-usage"
+			b.Color = Conf.CPUUsage.Color
+			b.Background = Conf.CPUUsage.Background
+
+			if Conf.CPUUsage.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CPUUsage.Separator.Left.Color,
+					Conf.CPUUsage.Separator.Left.Background,
+					Conf.CPUUsage.Separator.Left.Font,
+					Conf.CPUUsage.Separator.Left.FontSize,
+					Conf.CPUUsage.Separator.Left.Symbol,
+				)
+			}
+
+			// Click toggles between summary and per-core view.
+			usage := Conf.Values.CPUUsage
+
+			if Conf.Values.CPUUsagePerCore {
+				usage = Conf.Values.CPUUsageCores
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>CPU: %s</span>",
+				Conf.CPUUsage.Color,
+				Conf.CPUUsage.Background,
+				Conf.CPUUsage.Font,
+				Conf.CPUUsage.FontSize,
+				usage,
+			)
+
+			if Conf.CPUUsage.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CPUUsage.Separator.Right.Color,
+					Conf.CPUUsage.Separator.Right.Background,
+					Conf.CPUUsage.Separator.Right.Font,
+					Conf.CPUUsage.Separator.Right.FontSize,
+					Conf.CPUUsage.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		if Conf.CPUFreq.Enabled && Conf.Values.CPUFreq != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "cpu-freq"
+			b.Color = Conf.CPUFreq.Color
+			b.Background = Conf.CPUFreq.Background
+
+			if Conf.CPUFreq.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CPUFreq.Separator.Left.Color,
+					Conf.CPUFreq.Separator.Left.Background,
+					Conf.CPUFreq.Separator.Left.Font,
+					Conf.CPUFreq.Separator.Left.FontSize,
+					Conf.CPUFreq.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.CPUFreq.Color,
+				Conf.CPUFreq.Background,
+				Conf.CPUFreq.Font,
+				Conf.CPUFreq.FontSize,
+				Conf.Values.CPUFreq,
+			)
+
+			if Conf.CPUFreq.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.CPUFreq.Separator.Right.Color,
+					Conf.CPUFreq.Separator.Right.Background,
+					Conf.CPUFreq.Separator.Right.Font,
+					Conf.CPUFreq.Separator.Right.FontSize,
+					Conf.CPUFreq.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
+		// Psi block is empty if kernel does not support pressure stall information.
+		if Conf.PSI.Enabled && Conf.Values.PSI != "" {
+			var b lib.I3BarOutBlock
+
+			b.Name = "psi"
+			b.Color = Conf.PSI.Color
+			b.Background = Conf.PSI.Background
+			b.Urgent = Conf.Values.PSIUrgent
+
+			if Conf.PSI.Separator.Left.Enabled {
+				b.FullText = fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.PSI.Separator.Left.Color,
+					Conf.PSI.Separator.Left.Background,
+					Conf.PSI.Separator.Left.Font,
+					Conf.PSI.Separator.Left.FontSize,
+					Conf.PSI.Separator.Left.Symbol,
+				)
+			}
+
+			b.FullText += Conf.Values.PSI
+
+			if Conf.PSI.Separator.Right.Enabled {
+				b.FullText += fmt.Sprintf(
+					"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+					Conf.PSI.Separator.Right.Color,
+					Conf.PSI.Separator.Right.Background,
+					Conf.PSI.Separator.Right.Font,
+					Conf.PSI.Separator.Right.FontSize,
+					Conf.PSI.Separator.Right.Symbol,
+				)
+			}
+
+			b.Markup = "pango"
+			b.Separator = false
+
+			j = append(j, b)
+		}
+
 		if Conf.CPUTemp.Enabled {
 			var b lib.I3BarOutBlock
 
@@ -251,12 +783,12 @@ func main() {
 			}
 
 			b.FullText += fmt.Sprintf(
-				"<span color='%s' background='%s' font='%s' size='%s'>CPU: %d°</span>",
+				"<span color='%s' background='%s' font='%s' size='%s'>CPU: %s</span>",
 				Conf.CPUTemp.Color,
 				Conf.CPUTemp.Background,
 				Conf.CPUTemp.Font,
 				Conf.CPUTemp.FontSize,
-				Conf.Values.CPUTemperature,
+				lib.FormatTemperatures(Conf.CPUTemp.Aggregate, Conf.Values.CPUTemperature, Conf.Values.CPUTemperatures),
 			)
 
 			if Conf.CPUTemp.Separator.Right.Enabled {
@@ -293,28 +825,14 @@ func main() {
 				)
 			}
 
-			if Conf.Mem.ShowSwap {
-				b.FullText += fmt.Sprintf(
-					"<span color='%s' background='%s' font='%s' size='%s'>M:%d%% SHM:%dM SW:%dM</span>",
-					Conf.Mem.Color,
-					Conf.Mem.Background,
-					Conf.Mem.Font,
-					Conf.Mem.FontSize,
-					Conf.Values.Memory.Usedpct,
-					Conf.Values.Memory.Shared,
-					Conf.Values.Memory.Swap,
-				)
-			} else {
-				b.FullText += fmt.Sprintf(
-					"<span color='%s' background='%s' font='%s' size='%s'>M:%d%% SHM:%dM</span>",
-					Conf.Mem.Color,
-					Conf.Mem.Background,
-					Conf.Mem.Font,
-					Conf.Mem.FontSize,
-					Conf.Values.Memory.Usedpct,
-					Conf.Values.Memory.Shared,
-				)
-			}
+			b.FullText += fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				Conf.Mem.Color,
+				Conf.Mem.Background,
+				Conf.Mem.Font,
+				Conf.Mem.FontSize,
+				Conf.Values.Memory,
+			)
 
 			if Conf.Mem.Separator.Right.Enabled {
 				b.FullText += fmt.Sprintf(
@@ -336,8 +854,10 @@ func main() {
 		if Conf.LA.Enabled {
 			var b lib.I3BarOutBlock
 
-			b.Color = Conf.LA.Color
+			b.Name = "la"
+			b.Color = Conf.Values.LaColor
 			b.Background = Conf.LA.Background
+			b.Urgent = Conf.Values.LaUrgent
 
 			if Conf.LA.Separator.Left.Enabled {
 				b.FullText = fmt.Sprintf(
@@ -352,7 +872,7 @@ func main() {
 
 			b.FullText += fmt.Sprintf(
 				"<span color='%s' background='%s' font='%s' size='%s'>LA:%s</span>",
-				Conf.LA.Color,
+				Conf.Values.LaColor,
 				Conf.LA.Background,
 				Conf.LA.Font,
 				Conf.LA.FontSize,
@@ -465,6 +985,8 @@ func main() {
 		if Conf.Battery.Enabled {
 			var b lib.I3BarOutBlock
 
+			b.Name = "battery"
+			b.Urgent = Conf.Values.BatteryUrgent
 			b.Color = Conf.Battery.Color
 			b.Background = Conf.Battery.Background
 
@@ -479,7 +1001,12 @@ func main() {
 				)
 			}
 
-			b.FullText += Conf.Values.BatteryString
+			// In aggregate mode click toggles between combined battery and per-battery breakdown.
+			if Conf.Battery.Aggregate && Conf.Values.BatteryShowAll {
+				b.FullText += Conf.Values.BatteryBreakdown
+			} else {
+				b.FullText += Conf.Values.BatteryString
+			}
 
 			if Conf.Battery.Separator.Right.Enabled {
 				b.FullText += fmt.Sprintf(
@@ -646,3 +1173,20 @@ func PrintToI3bar(c *lib.MyConfig) {
 		fmt.Println(strings.TrimSuffix(buf.String(), "\n") + ",")
 	}
 }
+
+// batteryReport prints summary of recorded battery history.
+func batteryReport() {
+	file, err := lib.BatteryHistoryFile()
+
+	if err != nil {
+		log.Fatalf("Unable to locate battery history file: %s", err)
+	}
+
+	samples, err := lib.ReadBatteryHistory(file)
+
+	if err != nil {
+		log.Fatalf("Unable to read battery history: %s", err)
+	}
+
+	lib.BatteryReport(os.Stdout, samples)
+}
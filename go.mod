@@ -4,7 +4,6 @@ go 1.24.2
 
 require (
 	github.com/adrg/xdg v0.5.3
-	github.com/davecgh/go-spew v1.1.1
 	github.com/distatus/battery v0.11.0
 	github.com/go-co-op/gocron/v2 v2.16.1
 	github.com/hjson/hjson-go v3.3.0+incompatible
@@ -28,7 +27,6 @@ require (
 )
 
 tool (
-	i3status-go/cmd/battery-test
 	i3status-go/cmd/i3status-go
 	i3status-go/internal/lib
 )
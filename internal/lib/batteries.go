@@ -4,9 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"os"
-	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,7 +20,16 @@ func (c *MyConfig) UpdateBatteryInfo() {
 		ticker             = time.NewTicker(Delay)
 	)
 
-	for range ticker.C {
+	batteryRates.size = c.Battery.RateSamples
+
+	batteryLog.Load(c)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-batteryRefresh:
+		}
+
 		if Delay == InitialDelay {
 			Delay = LoopIterationDelay
 			ticker.Reset(Delay)
@@ -30,226 +37,445 @@ func (c *MyConfig) UpdateBatteryInfo() {
 
 		var (
 			batteries = []*battery.Battery{}
+			cycles    []int
+			percents  []bool
+			ac        bool
 			Batts     string
 			ch        int
-			status    string
 		)
 
-		if c.Battery.UseSysfs {
-			for _, file := range c.Battery.SysfsFiles {
-				var batt battery.Battery
-
-				_, err := os.Stat(file)
-
-				// If file exists.
-				if !os.IsNotExist(err) {
-					var (
-						myCharge string
-						myStatus string
-					)
-
-					b, err := os.ReadFile(file)
-
-					if err != nil {
-						log.Printf("Unable to read file %s: %s", file, err)
-
-						continue
-					}
-
-					myCharge = strings.Trim(string(b), "\n")
-
-					statusFile := filepath.Dir(file) + "/status"
-
-					// If file exists.
-					if !os.IsNotExist(err) {
-						b, err := os.ReadFile(statusFile)
-
-						if err != nil {
-							log.Printf("Unable to read file %s: %s", statusFile, err)
-
-							continue
-						}
-
-						myStatus = strings.Trim(string(b), "\n")
-					} else {
-						continue
-					}
-
-					// Make stub battery class :) .
-					switch myStatus {
-					case "Charging":
-						batt.State.Raw = battery.Charging
-					case "Discharging":
-						batt.State.Raw = battery.Discharging
-					case "Empty":
-						batt.State.Raw = battery.Empty
-					case "Full":
-						batt.State.Raw = battery.Full
-					default:
-						batt.State.Raw = battery.Unknown
-					}
-
-					ch, err := strconv.Atoi(myCharge)
-
-					if err != nil {
-						log.Printf("Unable to convert string from file %s to integer", file)
+		// AC state is known only from sysfs, so it is scanned even if batteries come from other source.
+		supplies, err := ScanPowerSupplies(c.Battery.SysfsRoot)
 
-						continue
-					}
+		if err != nil && c.Battery.UseSysfs {
+			log.Printf("Unable to scan power supplies in %s: %s", c.Battery.SysfsRoot, err)
+		}
 
-					batt.Current = float64(ch)
+		for _, s := range supplies {
+			if s.IsAC() {
+				ac = true
+			}
+		}
 
-					batteries = append(batteries, &batt)
+		if c.Battery.UseSysfs {
+			for _, s := range supplies {
+				if s.Type != "Battery" || !c.SysfsBatteryWanted(s) {
+					continue
 				}
+
+				batteries = append(batteries, s.Battery())
+				cycles = append(cycles, s.CycleCount)
+				percents = append(percents, !s.HasEnergy())
 			}
 		} else {
 			// In theory, this module should give for each entry its separate err, but in practice it gives
 			// one single err for all entries, so we cannot detemine whist exatly entry errored.
 			batteries, _ = battery.GetAll()
+			cycles = make([]int, len(batteries))
+			percents = make([]bool, len(batteries))
 		}
 
 		var (
 			battsInfo string
-			chStr     string
+			total     batteryTotal
 		)
 
 		for i, b := range batteries {
-			switch b.State.Raw {
-			case battery.Charging:
-				status = `▲`
-			case battery.Discharging:
-				status = `▼`
-			case battery.Empty:
-				status = `✘`
-			default:
-				status = `•`
+			rate := batteryRates.Add(i, b)
+
+			if b.Full <= 0 {
+				continue
 			}
 
 			// N.B. there can be case when battery is overcharged and shows >100%. It also can indicate that
 			//      calibration data is out of date and battery should be re-calibrated.
-			if c.Battery.UseSysfs {
-				ch = int(b.Current)
-			} else {
-				ch = int(math.Round((b.Full - (b.Full - b.Current)) * (100 / b.Full)))
+			ch = int(math.Round(b.Current / b.Full * 100))
+
+			if ch < 0 || ch > 500 {
+				continue
 			}
 
-			switch {
-			case ch <= 500 && ch >= 84:
-				if c.Battery.ChargeColor.Full == "" {
-					chStr = fmt.Sprintf(
-						"<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>",
-						c.Battery.Color,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				} else {
-					chStr = fmt.Sprintf(
-						`<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>`,
-						c.Battery.ChargeColor.Full,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				}
+			total.Add(b, rate, percents[i])
 
-			case ch < 85 && ch > 40:
-				if c.Battery.ChargeColor.AlmostFull == "" {
-					chStr = fmt.Sprintf(
-						"<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>",
-						c.Battery.Color,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				} else {
-					chStr = fmt.Sprintf(
-						`<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>`,
-						c.Battery.ChargeColor.AlmostFull,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				}
+			details := JoinNonEmpty(c.BatteryEstimate(b, ch, rate), c.BatteryHealth(b, cycles[i]))
+			battsInfo += c.RenderBattery(fmt.Sprintf("B%d ", i), ch, b.State.Raw, details)
+		}
 
-			case ch <= 40 && ch >= 10:
-				if c.Battery.ChargeColor.AlmostEmpty == "" {
-					chStr = fmt.Sprintf(
-						"<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>",
-						c.Battery.Color,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				} else {
-					chStr = fmt.Sprintf(
-						`<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>`,
-						c.Battery.ChargeColor.AlmostEmpty,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				}
+		if battsInfo != "" {
+			Batts = battsInfo
+		}
 
-			case ch < 10 && ch >= 0:
-				if c.Battery.ChargeColor.Empty == "" {
-					chStr = fmt.Sprintf(
-						"<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>",
-						c.Battery.Color,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				} else {
-					chStr = fmt.Sprintf(
-						`<span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span>`,
-						c.Battery.ChargeColor.Empty,
-						c.Battery.Background,
-						c.Battery.Font,
-						c.Battery.FontSize,
-						ch,
-					)
-				}
+		var (
+			breakdown = Batts
+			urgent    bool
+		)
 
-			default:
-				continue
+		if total.count > 0 {
+			b, ch, rate := total.Battery()
+
+			if c.Battery.Aggregate {
+				Batts = c.RenderBattery("", ch, b.State.Raw, c.BatteryEstimate(b, ch, rate))
 			}
 
-			battsInfo += fmt.Sprintf(
-				"<span color='%s' background='%s' font='%s' size='%s'>%s</span><span color='%s' background='%s' font='%s' size='%s'>B%d </span>%s<span color='%s' background='%s' font='%s' size='%s'> %s</span>",
+			// Laptop runs until all batteries are empty, so actions are based on combined charge.
+			urgent = batteryAlarms.Check(c, ch, b.State.Raw, ac)
+
+			batteryLog.Add(c, BatterySample{
+				Time:    time.Now(),
+				Percent: ch,
+				State:   b.State.Raw.String(),
+				Power:   rate / 1000,
+				Full:    b.Full / 1000,
+				Design:  b.Design / 1000,
+			})
+		}
+
+		if ac && c.Battery.ShowPlug && Batts != "" {
+			plug := fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s </span>",
 				c.Battery.Color,
 				c.Battery.Background,
 				c.Battery.SymbolFont,
 				c.Battery.SymbolFontSize,
-				c.Battery.Symbol,
-				c.Battery.Color,
-				c.Battery.Background,
-				c.Battery.Font,
-				c.Battery.FontSize,
-				i,
-				chStr,
+				c.Battery.PlugSymbol,
+			)
+
+			Batts = plug + Batts
+			breakdown = plug + breakdown
+		}
+
+		if limit := c.RenderChargeLimit(); limit != "" && Batts != "" {
+			Batts += limit
+			breakdown += limit
+		}
+
+		if c.Battery.History.Sparkline && Batts != "" {
+			spark := fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'> %s</span>",
 				c.Battery.Color,
 				c.Battery.Background,
 				c.Battery.Font,
 				c.Battery.FontSize,
-				status,
+				batteryLog.Sparkline(c),
 			)
-		}
 
-		if battsInfo != "" {
-			Batts = battsInfo
+			Batts += spark
+			breakdown += spark
 		}
 
-		if c.Values.BatteryString != Batts {
+		if c.Values.BatteryString != Batts || c.Values.BatteryBreakdown != breakdown || c.Values.BatteryUrgent != urgent {
 			c.Values.BatteryString = Batts
+			c.Values.BatteryBreakdown = breakdown
+			c.Values.BatteryUrgent = urgent
 			c.Channels.UpdateReady <- true
 		}
 	}
 }
+
+// RenderBattery returns pango-formatted text of one battery or of all batteries combined. Label is shown after
+// battery symbol, charge is in percents.
+func (c *MyConfig) RenderBattery(label string, charge int, state battery.AgnosticState, estimate string) string {
+	var status string
+
+	switch state {
+	case battery.Charging:
+		status = `▲`
+	case battery.Discharging:
+		status = `▼`
+	case battery.Empty:
+		status = `✘`
+	default:
+		status = `•`
+	}
+
+	str := fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>%s</span><span color='%s' background='%s' font='%s' size='%s'>%s</span><span color='%s' background='%s' font='%s' size='%s'>% 3d%%</span><span color='%s' background='%s' font='%s' size='%s'> %s</span>",
+		c.Battery.Color,
+		c.Battery.Background,
+		c.Battery.SymbolFont,
+		c.Battery.SymbolFontSize,
+		c.Battery.Symbol,
+		c.Battery.Color,
+		c.Battery.Background,
+		c.Battery.Font,
+		c.Battery.FontSize,
+		label,
+		c.BatteryChargeColor(charge),
+		c.Battery.Background,
+		c.Battery.Font,
+		c.Battery.FontSize,
+		charge,
+		c.Battery.Color,
+		c.Battery.Background,
+		c.Battery.Font,
+		c.Battery.FontSize,
+		status,
+	)
+
+	if estimate != "" {
+		str += fmt.Sprintf(
+			"<span color='%s' background='%s' font='%s' size='%s'> %s</span>",
+			c.Battery.Color,
+			c.Battery.Background,
+			c.Battery.Font,
+			c.Battery.FontSize,
+			estimate,
+		)
+	}
+
+	return str
+}
+
+// BatteryChargeColor returns color of given charge level, in percents.
+func (c *MyConfig) BatteryChargeColor(charge int) string {
+	var color string
+
+	switch {
+	case charge >= 84:
+		color = c.Battery.ChargeColor.Full
+	case charge > 40:
+		color = c.Battery.ChargeColor.AlmostFull
+	case charge >= 10:
+		color = c.Battery.ChargeColor.AlmostEmpty
+	default:
+		color = c.Battery.ChargeColor.Empty
+	}
+
+	if color == "" {
+		return c.Battery.Color
+	}
+
+	return color
+}
+
+// batteryTotal sums energy and charge rates of several batteries. Batteries that report only percents are not
+// counted in energy, if there are any, charge is plain average of percents.
+type batteryTotal struct {
+	count       int
+	percentOnly int
+	percent     float64
+	energy      float64
+	full        float64
+	design      float64
+	charging    float64
+	discharging float64
+	states      map[battery.AgnosticState]int
+}
+
+// Add adds battery with averaged charge rate in mW. Battery must have known capacity, percentOnly means that its
+// capacity is not in mWh, but in percents.
+func (t *batteryTotal) Add(b *battery.Battery, rate float64, percentOnly bool) {
+	if t.states == nil {
+		t.states = map[battery.AgnosticState]int{}
+	}
+
+	t.count++
+	t.percent += b.Current / b.Full * 100
+	t.states[b.State.Raw]++
+
+	if percentOnly {
+		t.percentOnly++
+	} else {
+		t.energy += b.Current
+		t.full += b.Full
+		t.design += b.Design
+	}
+
+	switch b.State.Raw {
+	case battery.Charging:
+		t.charging += rate
+	case battery.Discharging:
+		t.discharging += rate
+	}
+}
+
+// Battery returns combined battery, its charge in percents, weighted by capacity if all batteries report energy, and
+// net charge rate in mW. One battery can be charged while other one is discharged, so combined state depends on which
+// rate is greater.
+func (t *batteryTotal) Battery() (*battery.Battery, int, float64) {
+	var (
+		b    battery.Battery
+		rate = math.Abs(t.charging - t.discharging)
+	)
+
+	switch {
+	case t.discharging > t.charging:
+		b.State.Raw = battery.Discharging
+	case t.charging > 0:
+		b.State.Raw = battery.Charging
+	case t.states[battery.Discharging] > 0:
+		b.State.Raw = battery.Discharging
+	case t.states[battery.Charging] > 0:
+		b.State.Raw = battery.Charging
+	case t.states[battery.Full] == t.count:
+		b.State.Raw = battery.Full
+	case t.states[battery.Empty] == t.count:
+		b.State.Raw = battery.Empty
+	default:
+		b.State.Raw = battery.Unknown
+	}
+
+	b.Current = t.energy
+	b.Full = t.full
+	b.Design = t.design
+
+	if t.percentOnly == 0 {
+		return &b, int(math.Round(t.energy / t.full * 100)), rate
+	}
+
+	percent := t.percent / float64(t.count)
+
+	// Energy is not known at all, so combined battery is in percents too.
+	if t.full == 0 {
+		b.Current = percent
+		b.Full = 100
+	}
+
+	return &b, int(math.Round(percent)), rate
+}
+
+// batteryAlarm remembers which low battery actions have fired in current discharge cycle.
+type batteryAlarm struct {
+	fired  map[int]bool
+	urgent bool
+}
+
+// batteryAlarms is accessed only from UpdateBatteryInfo goroutine.
+var batteryAlarms batteryAlarm
+
+// Check runs low battery actions whose level is reached and returns whether battery is at critical level. Each action
+// runs once per discharge cycle: it is re-armed when charge rises above level + hysteresis or battery is not
+// discharging anymore, e.g. AC is plugged in.
+func (a *batteryAlarm) Check(c *MyConfig, charge int, state battery.AgnosticState, ac bool) bool {
+	if a.fired == nil {
+		a.fired = map[int]bool{}
+	}
+
+	if ac || (state != battery.Discharging && state != battery.Empty) {
+		clear(a.fired)
+		a.urgent = false
+
+		return false
+	}
+
+	for i, action := range c.Battery.Actions {
+		switch {
+		case charge <= action.Level && !a.fired[i]:
+			a.fired[i] = true
+			c.Channels.RunChan <- action.Cmd
+		case charge > action.Level+c.Battery.Hysteresis:
+			a.fired[i] = false
+		}
+	}
+
+	switch {
+	case charge <= c.Battery.CriticalLevel:
+		a.urgent = true
+	case charge > c.Battery.CriticalLevel+c.Battery.Hysteresis:
+		a.urgent = false
+	}
+
+	return a.urgent
+}
+
+// rateSamples keeps last charge rate samples of each battery, they are reset when battery changes its state.
+type rateSamples struct {
+	size    int
+	states  map[int]battery.AgnosticState
+	samples map[int][]float64
+}
+
+// batteryRates is accessed only from UpdateBatteryInfo goroutine.
+var batteryRates rateSamples
+
+// Add appends current charge rate of i-th battery and returns moving average of last samples, in mW.
+func (r *rateSamples) Add(i int, b *battery.Battery) float64 {
+	if r.states == nil {
+		r.states = map[int]battery.AgnosticState{}
+		r.samples = map[int][]float64{}
+	}
+
+	if r.states[i] != b.State.Raw {
+		r.states[i] = b.State.Raw
+		r.samples[i] = nil
+	}
+
+	if b.ChargeRate <= 0 {
+		return 0
+	}
+
+	r.samples[i] = append(r.samples[i], b.ChargeRate)
+
+	if len(r.samples[i]) > r.size {
+		r.samples[i] = r.samples[i][len(r.samples[i])-r.size:]
+	}
+
+	var sum float64
+
+	for _, s := range r.samples[i] {
+		sum += s
+	}
+
+	return sum / float64(len(r.samples[i]))
+}
+
+// BatteryEstimate returns time left or time to full and power draw of battery, as configured. Charge is in percents,
+// rate is averaged charge rate in mW.
+func (c *MyConfig) BatteryEstimate(b *battery.Battery, charge int, rate float64) string {
+	var estimate []string
+
+	if rate <= 0 {
+		return ""
+	}
+
+	if c.Battery.ShowTime && b.Full > 0 {
+		energy := b.Full * float64(charge) / 100
+
+		switch b.State.Raw {
+		case battery.Discharging:
+			estimate = append(estimate, FormatSeconds(int64(energy/rate*3600))+" left")
+		case battery.Charging:
+			estimate = append(estimate, FormatSeconds(int64(math.Max(b.Full-energy, 0)/rate*3600))+" to full")
+		}
+	}
+
+	if c.Battery.ShowPower {
+		estimate = append(estimate, fmt.Sprintf("%.1fW", rate/1000))
+	}
+
+	return strings.Join(estimate, " ")
+}
+
+// BatteryHealth returns last full capacity as percent of design capacity and cycle count, if configured and known.
+func (c *MyConfig) BatteryHealth(b *battery.Battery, cycles int) string {
+	var health []string
+
+	if !c.Battery.ShowHealth {
+		return ""
+	}
+
+	if b.Design > 0 {
+		health = append(health, fmt.Sprintf("♥%d%%", int(math.Round(b.Full/b.Design*100))))
+	}
+
+	if cycles > 0 {
+		health = append(health, fmt.Sprintf("↻%d", cycles))
+	}
+
+	return strings.Join(health, " ")
+}
+
+// SysfsBatteryWanted returns true if battery should be shown: it is listed in config or, if list is empty, it is not a
+// battery of peripheral device like mouse.
+func (c *MyConfig) SysfsBatteryWanted(s PowerSupply) bool {
+	if len(c.Battery.Batteries) > 0 {
+		return slices.Contains(c.Battery.Batteries, s.Name)
+	}
+
+	return s.Scope != "Device" || c.Battery.IncludeDevices
+}
+
+// JoinNonEmpty joins non-empty strings with space.
+func JoinNonEmpty(s ...string) string {
+	return strings.Join(slices.DeleteFunc(s, func(e string) bool { return e == "" }), " ")
+}
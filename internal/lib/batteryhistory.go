@@ -0,0 +1,312 @@
+package lib
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// BatterySample is one record of battery history. Power is in W, capacities are in Wh, zero means unknown.
+type BatterySample struct {
+	Time    time.Time
+	Percent int
+	State   string
+	Power   float64
+	Full    float64
+	Design  float64
+}
+
+// batteryHistory keeps recent battery samples for sparkline and appends them to csv file.
+type batteryHistory struct {
+	file    string
+	last    time.Time
+	samples []BatterySample
+}
+
+// batteryLog is accessed only from UpdateBatteryInfo goroutine.
+var batteryLog batteryHistory
+
+// sparks are sparkline symbols from empty to full.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// BatteryHistoryFile returns path of battery history file under XDG state dir.
+func BatteryHistoryFile() (string, error) {
+	return xdg.StateFile("i3status-go/battery.csv")
+}
+
+// Load reads recent samples from history file, so sparkline survives restart.
+func (h *batteryHistory) Load(c *MyConfig) {
+	if !c.Battery.History.Enabled {
+		return
+	}
+
+	file, err := BatteryHistoryFile()
+
+	if err != nil {
+		log.Printf("Unable to locate battery history file, history will not be saved: %s", err)
+
+		return
+	}
+
+	h.file = file
+
+	samples, err := ReadBatteryHistory(file)
+
+	if err != nil {
+		log.Printf("Unable to read battery history: %s", err)
+	}
+
+	h.samples = samples
+	h.trim(c, time.Now())
+}
+
+// Add remembers sample and appends it to history file, no more often than configured interval.
+func (h *batteryHistory) Add(c *MyConfig, s BatterySample) {
+	if s.Time.Sub(h.last) < time.Duration(c.Battery.History.Interval)*time.Second {
+		return
+	}
+
+	h.last = s.Time
+	h.samples = append(h.samples, s)
+	h.trim(c, s.Time)
+
+	if h.file == "" {
+		return
+	}
+
+	if err := h.append(c, s); err != nil {
+		log.Printf("Unable to write battery history to %s: %s", h.file, err)
+	}
+}
+
+// trim drops samples older than sparkline period.
+func (h *batteryHistory) trim(c *MyConfig, now time.Time) {
+	oldest := now.Add(-time.Duration(c.Battery.History.SparklineHours) * time.Hour)
+
+	for len(h.samples) > 0 && h.samples[0].Time.Before(oldest) {
+		h.samples = h.samples[1:]
+	}
+}
+
+// append writes sample to history file. When file grows over max size, it is renamed to file.1, previous file.1 is
+// dropped.
+func (h *batteryHistory) append(c *MyConfig, s BatterySample) error {
+	if st, err := os.Stat(h.file); err == nil && st.Size() >= int64(c.Battery.History.MaxSize)*1024 {
+		if err := os.Rename(h.file, h.file+".1"); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(h.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+
+	if err := w.Write([]string{
+		strconv.FormatInt(s.Time.Unix(), 10),
+		strconv.Itoa(s.Percent),
+		s.State,
+		strconv.FormatFloat(s.Power, 'f', 2, 64),
+		strconv.FormatFloat(s.Full, 'f', 2, 64),
+		strconv.FormatFloat(s.Design, 'f', 2, 64),
+	}); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// Sparkline returns charge over configured period as unicode sparkline, periods without samples are blank.
+func (h *batteryHistory) Sparkline(c *MyConfig) string {
+	var (
+		width  = c.Battery.History.SparklineWidth
+		period = time.Duration(c.Battery.History.SparklineHours) * time.Hour
+		from   = time.Now().Add(-period)
+		sum    = make([]int, width)
+		count  = make([]int, width)
+		line   = make([]rune, width)
+	)
+
+	for _, s := range h.samples {
+		i := int(s.Time.Sub(from) * time.Duration(width) / period)
+
+		if i < 0 || i >= width {
+			continue
+		}
+
+		sum[i] += s.Percent
+		count[i]++
+	}
+
+	for i := range line {
+		if count[i] == 0 {
+			line[i] = ' '
+
+			continue
+		}
+
+		level := sum[i] / count[i] * len(sparks) / 101
+		line[i] = sparks[max(0, min(level, len(sparks)-1))]
+	}
+
+	return string(line)
+}
+
+// ReadBatteryHistory reads samples from rotated and current history files, malformed lines are skipped.
+func ReadBatteryHistory(file string) ([]BatterySample, error) {
+	var samples []BatterySample
+
+	for _, name := range []string{file + ".1", file} {
+		f, err := os.Open(name)
+
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return samples, err
+		}
+
+		r := csv.NewReader(f)
+		r.FieldsPerRecord = -1
+
+		for {
+			rec, err := r.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil || len(rec) < 6 {
+				continue
+			}
+
+			var s BatterySample
+
+			ts, err := strconv.ParseInt(rec[0], 10, 64)
+
+			if err != nil {
+				continue
+			}
+
+			s.Time = time.Unix(ts, 0)
+			s.Percent, _ = strconv.Atoi(rec[1])
+			s.State = rec[2]
+			s.Power, _ = strconv.ParseFloat(rec[3], 64)
+			s.Full, _ = strconv.ParseFloat(rec[4], 64)
+			s.Design, _ = strconv.ParseFloat(rec[5], 64)
+
+			samples = append(samples, s)
+		}
+
+		f.Close()
+	}
+
+	return samples, nil
+}
+
+// BatteryReport prints summary of battery history: discharge and charge rates and capacity wear.
+func BatteryReport(w io.Writer, samples []BatterySample) {
+	// Longer gap between samples means that program was not running or machine was suspended.
+	const maxGap = 10 * time.Minute
+
+	if len(samples) == 0 {
+		fmt.Fprintln(w, "No battery history recorded yet.")
+
+		return
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+
+	fmt.Fprintf(w, "Period:       %s - %s, %d samples\n",
+		first.Time.Format(time.DateTime), last.Time.Format(time.DateTime), len(samples))
+
+	var (
+		dischargeHours, dischargePct, dischargeWh float64
+		chargeHours, chargePct                    float64
+	)
+
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1], samples[i]
+		dt := cur.Time.Sub(prev.Time)
+
+		if dt <= 0 || dt > maxGap || prev.State != cur.State {
+			continue
+		}
+
+		hours := dt.Hours()
+
+		switch cur.State {
+		case "Discharging":
+			dischargeHours += hours
+			dischargePct += float64(prev.Percent - cur.Percent)
+			dischargeWh += (prev.Power + cur.Power) / 2 * hours
+		case "Charging":
+			chargeHours += hours
+			chargePct += float64(cur.Percent - prev.Percent)
+		}
+	}
+
+	if dischargeHours > 0 && dischargePct > 0 {
+		rate := dischargePct / dischargeHours
+
+		fmt.Fprintf(w, "Discharging:  %s on battery, %.1f%%/h, %.1f W average, full charge lasts %s\n",
+			FormatSeconds(int64(dischargeHours*3600)), rate, dischargeWh/dischargeHours, FormatSeconds(int64(100/rate*3600)))
+	} else {
+		fmt.Fprintln(w, "Discharging:  not enough data")
+	}
+
+	if chargeHours > 0 && chargePct > 0 {
+		rate := chargePct / chargeHours
+
+		fmt.Fprintf(w, "Charging:     %.1f%%/h, full charge takes %s\n", rate, FormatSeconds(int64(100/rate*3600)))
+	} else {
+		fmt.Fprintln(w, "Charging:     not enough data")
+	}
+
+	var oldest, newest *BatterySample
+
+	for i := range samples {
+		if samples[i].Full > 0 && samples[i].Design > 0 {
+			if oldest == nil {
+				oldest = &samples[i]
+			}
+
+			newest = &samples[i]
+		}
+	}
+
+	if newest == nil {
+		fmt.Fprintln(w, "Capacity:     unknown")
+
+		return
+	}
+
+	fmt.Fprintf(w, "Capacity:     %.1f Wh of %.1f Wh design, wear %.1f%%\n",
+		newest.Full, newest.Design, math.Max(0, 100-newest.Full/newest.Design*100))
+
+	if days := newest.Time.Sub(oldest.Time).Hours() / 24; days >= 1 {
+		fmt.Fprintf(w, "Wear trend:   %+.2f Wh over %.0f days\n", newest.Full-oldest.Full, days)
+	}
+}
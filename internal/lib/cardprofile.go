@@ -0,0 +1,244 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	p "github.com/mafik/pulseaudio"
+)
+
+// cardPA is connection to pulseaudio server used by card profile block, it is independent of volume block.
+var cardPA struct {
+	mu     sync.Mutex
+	client *p.Client
+}
+
+// UpdateCardProfile tracks active profile of configured sound card. Connection to pulseaudio server is re-established
+// with exponential backoff, same way as for volume block.
+func (c *MyConfig) UpdateCardProfile() {
+	backoff := paMinBackoff
+
+	for {
+		client, err := PaNewClient()
+
+		if err != nil {
+			log.Printf("Unable to connect to pulseaudio server, retry in %s: %s", backoff, err)
+
+			c.SetCardProfile(c.CardProfile.Symbol + " " + html.EscapeString(c.CardProfile.UnavailableText))
+			time.Sleep(backoff)
+
+			backoff = min(backoff*2, paMaxBackoff)
+
+			continue
+		}
+
+		backoff = paMinBackoff
+
+		cardPA.mu.Lock()
+		cardPA.client = client
+		cardPA.mu.Unlock()
+
+		if err := c.WatchCardProfile(); err != nil {
+			log.Printf("Lost connection to pulseaudio server: %s", err)
+		}
+
+		cardPA.mu.Lock()
+		cardPA.client = nil
+		cardPA.mu.Unlock()
+
+		client.Close()
+		c.SetCardProfile(c.CardProfile.Symbol + " " + html.EscapeString(c.CardProfile.UnavailableText))
+	}
+}
+
+// WatchCardProfile updates card profile block on every pulseaudio update event until connection looks dead.
+func (c *MyConfig) WatchCardProfile() error {
+	if err := c.RefreshCardProfile(); err != nil {
+		return err
+	}
+
+	updates, err := CardCall(func(pa *p.Client) (<-chan struct{}, error) { return pa.Updates() })
+
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to pulseaudio updates: %w", err)
+	}
+
+	ticker := time.NewTicker(paCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case _, ok := <-updates:
+			if !ok {
+				return errors.New("pulseaudio updates channel closed") //nolint: err113
+			}
+
+		// Periodic refresh is our health check.
+		case <-ticker.C:
+		}
+
+		if err := c.RefreshCardProfile(); err != nil {
+			return err
+		}
+	}
+}
+
+// CardCall runs fn with pulseaudio client of card profile block, see PaClientCall.
+func CardCall[T any](fn func(pa *p.Client) (T, error)) (T, error) {
+	cardPA.mu.Lock()
+	client := cardPA.client
+	cardPA.mu.Unlock()
+
+	return PaClientCall(client, fn)
+}
+
+// RefreshCardProfile gets active profile of configured card and renders it for i3bar.
+func (c *MyConfig) RefreshCardProfile() error {
+	card, err := c.FindCard()
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case card == nil && c.CardProfile.HideMissing:
+		c.SetCardProfile("")
+	case card == nil:
+		c.SetCardProfile(c.CardProfile.Symbol + " " + html.EscapeString(c.CardProfile.MissingText))
+	case card.ActiveProfile == nil:
+		c.SetCardProfile(c.CardProfile.Symbol + " ?")
+	default:
+		c.SetCardProfile(c.CardProfile.Symbol + " " + html.EscapeString(c.ProfileAlias(card.ActiveProfile.Name, card.ActiveProfile.Description)))
+	}
+
+	return nil
+}
+
+// SetCardProfile sets text of card profile block and asks for bar update if it has changed.
+func (c *MyConfig) SetCardProfile(str string) {
+	if c.Values.CardProfile != str {
+		c.Values.CardProfile = str
+		c.Channels.UpdateReady <- true
+	}
+}
+
+// FindCard returns first card which name or description matches configured regexp, or nil if there is no such card.
+func (c *MyConfig) FindCard() (*p.Card, error) {
+	cards, err := CardCall(func(pa *p.Client) ([]p.Card, error) { return pa.Cards() })
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to get list of pulseaudio cards: %w", err)
+	}
+
+	for i := range cards {
+		if c.CardProfile.CardRe.MatchString(cards[i].Name) ||
+			c.CardProfile.CardRe.MatchString(cards[i].PropList["device.description"]) {
+			return &cards[i], nil
+		}
+	}
+
+	return nil, nil //nolint: nilnil
+}
+
+// ProfileAlias returns short label of given profile: alias from config, if any, or profile description.
+func (c *MyConfig) ProfileAlias(name, description string) string {
+	if alias, exist := c.CardProfile.Aliases[name]; exist {
+		return alias
+	}
+
+	if alias, exist := c.CardProfile.Aliases[description]; exist {
+		return alias
+	}
+
+	return description
+}
+
+// CardProfiles returns list of profiles to cycle through: configured ones present on card or all available profiles of
+// card sorted by priority.
+func (c *MyConfig) CardProfiles(card *p.Card) []string {
+	var profiles []string
+
+	if len(c.CardProfile.Profiles) > 0 {
+		for _, name := range c.CardProfile.Profiles {
+			if _, exist := card.Profiles[name]; exist {
+				profiles = append(profiles, name)
+			}
+		}
+
+		return profiles
+	}
+
+	for name, profile := range card.Profiles {
+		if profile.Available != 0 {
+			profiles = append(profiles, name)
+		}
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return card.Profiles[profiles[i]].Priority > card.Profiles[profiles[j]].Priority
+	})
+
+	return profiles
+}
+
+// CardProfileHandler switches card to next or previous profile by click or mouse wheel.
+func (c *MyConfig) CardProfileHandler(e ClickEvent) {
+	var dir int
+
+	switch e.Button {
+	case c.CardProfile.NextButton, c.CardProfile.WheelDown:
+		dir = 1
+	case c.CardProfile.PrevButton, c.CardProfile.WheelUp:
+		dir = -1
+	default:
+		return
+	}
+
+	card, err := c.FindCard()
+
+	if err != nil {
+		log.Print(err)
+
+		return
+	}
+
+	if card == nil {
+		return
+	}
+
+	profiles := c.CardProfiles(card)
+
+	if len(profiles) == 0 {
+		return
+	}
+
+	// If active profile is not in list, next one is first and previous one is last.
+	i := -1
+
+	if card.ActiveProfile != nil {
+		i = slices.Index(profiles, card.ActiveProfile.Name)
+	}
+
+	switch {
+	case i < 0 && dir > 0:
+		i = 0
+	case i < 0:
+		i = len(profiles) - 1
+	default:
+		i = (i + dir + len(profiles)) % len(profiles)
+	}
+
+	index := card.Index
+	name := profiles[i]
+
+	// Block itself is updated by pulseaudio update event.
+	if _, err := CardCall(func(pa *p.Client) (struct{}, error) { return struct{}{}, pa.SetCardProfile(index, name) }); err != nil {
+		log.Printf("Unable to set profile %s of pulseaudio card %s: %s", name, card.Name, err)
+	}
+}
@@ -0,0 +1,156 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const (
+	chargeStartFile = "charge_control_start_threshold"
+	chargeEndFile   = "charge_control_end_threshold"
+)
+
+// batteryRefresh asks UpdateBatteryInfo to update block right now instead of waiting for next tick.
+var batteryRefresh = make(chan struct{}, 1)
+
+// ChargeLimitDir returns sysfs dir of battery which charge limit is controlled: configured one or first battery that
+// supports charge control thresholds.
+func (c *MyConfig) ChargeLimitDir() (string, error) {
+	if c.Battery.ChargeLimit.Battery != "" {
+		return filepath.Join(c.Battery.SysfsRoot, c.Battery.ChargeLimit.Battery), nil
+	}
+
+	supplies, err := ScanPowerSupplies(c.Battery.SysfsRoot)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, s := range supplies {
+		dir := filepath.Join(c.Battery.SysfsRoot, s.Name)
+
+		if _, err := os.Stat(filepath.Join(dir, chargeEndFile)); err == nil && s.Type == "Battery" {
+			return dir, nil
+		}
+	}
+
+	return "", errors.New("no battery supports charge control thresholds") //nolint: err113
+}
+
+// ChargeLimit returns current start and end charge thresholds in percents. Start is 0 if battery has only end
+// threshold.
+func (c *MyConfig) ChargeLimit() (int, int, error) {
+	dir, err := c.ChargeLimitDir()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := ReadSysfsFloat(dir, chargeEndFile)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	start, _ := ReadSysfsFloat(dir, chargeStartFile)
+
+	return int(start), int(end), nil
+}
+
+// RenderChargeLimit returns pango-formatted charge limit or empty string if it is not shown or unknown.
+func (c *MyConfig) RenderChargeLimit() string {
+	if !c.Battery.ChargeLimit.Show {
+		return ""
+	}
+
+	_, end, err := c.ChargeLimit()
+
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'> %s%d%%</span>",
+		c.Battery.Color,
+		c.Battery.Background,
+		c.Battery.Font,
+		c.Battery.FontSize,
+		c.Battery.ChargeLimit.Symbol,
+		end,
+	)
+}
+
+// CycleChargeLimit switches battery to next charge limit preset.
+func (c *MyConfig) CycleChargeLimit() {
+	presets := c.Battery.ChargeLimit.Presets
+
+	if len(presets) == 0 {
+		return
+	}
+
+	dir, err := c.ChargeLimitDir()
+
+	if err != nil {
+		log.Printf("Unable to set battery charge limit: %s", err)
+
+		return
+	}
+
+	start, end, err := c.ChargeLimit()
+
+	if err != nil {
+		log.Printf("Unable to get battery charge limit: %s", err)
+
+		return
+	}
+
+	// If current limit is not one of presets, first preset is next.
+	next := 0
+
+	for i, p := range presets {
+		if p.End == end && (p.Start == 0 || p.Start == start) {
+			next = (i + 1) % len(presets)
+
+			break
+		}
+	}
+
+	p := presets[next]
+	newStart, setStart := p.Start, p.Start != 0
+
+	// Zero start means that preset does not touch it, unless current start is not below new end. Then it is lowered,
+	// keeping the same gap to end.
+	if !setStart && start >= p.End {
+		newStart, setStart = max(0, p.End-(end-start)), true
+	}
+
+	var writes []SysfsWrite
+
+	endWrite := SysfsWrite{File: filepath.Join(dir, chargeEndFile), Value: fmt.Sprint(p.End)}
+	writes = append(writes, endWrite)
+
+	// Driver refuses start threshold above end one, so start goes first when end is lowered and last when it is raised.
+	if setStart {
+		startWrite := SysfsWrite{File: filepath.Join(dir, chargeStartFile), Value: fmt.Sprint(newStart)}
+
+		if p.End < end {
+			writes = []SysfsWrite{startWrite, endWrite}
+		} else {
+			writes = append(writes, startWrite)
+		}
+	}
+
+	if err := WriteSysfsAll(writes, c.Battery.ChargeLimit.HelperCmd); err != nil {
+		log.Printf("Unable to set battery charge limit: %s", err)
+
+		return
+	}
+
+	select {
+	case batteryRefresh <- struct{}{}:
+	default:
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 
 	"github.com/adrg/xdg"
 	"github.com/hjson/hjson-go"
@@ -63,23 +64,47 @@ type MyConfig struct {
 	Values struct {
 		PrintOutput      bool
 		CPUTemperature   int64
+		CPUTemperatures  []int64
 		BatteryString    string
+		BatteryBreakdown string
+		BatteryShowAll   bool
+		BatteryUrgent    bool
 		ClockTime        string
 		IfStatus         string
 		VPNStatus        string
-		Memory           Mem
+		Memory           string
 		La               string
+		LaColor          string
+		LaUrgent         bool
 		PA               *p.Client
 		SoundVolume      string
 		RunCommandOutput string
+		BindingMode      string
+		WindowTitle      string
+		Layout           string
+		FocusTime        string
+		MicVolume        string
+		MicInUse         bool
+		StreamVolume     string
+		CardProfile      string
+		Sensors          string
+		SensorsUrgent    bool
+		CPUUsage         string
+		CPUUsageCores    string
+		CPUUsagePerCore  bool
+		CPUFreq          string
+		PSI              string
+		PSIUrgent        bool
 	}
 
 	Channels struct {
-		UpdateReady     chan bool
-		MsgChan         chan []I3BarOutBlock
-		SigChan         chan os.Signal
-		SVPAHandlerChan chan ClickEvent
-		RunChan         chan []string
+		UpdateReady       chan bool
+		MsgChan           chan []I3BarOutBlock
+		SigChan           chan os.Signal
+		SVPAHandlerChan   chan ClickEvent
+		MicHandlerChan    chan ClickEvent
+		StreamHandlerChan chan ClickEvent
+		RunChan           chan []string
 	}
 
 	// Default text color
@@ -104,6 +129,18 @@ type MyConfig struct {
 		Font       string    `json:"font,omitempty"`
 		FontSize   string    `json:"font_size,omitempty"`
 		Separator  Separator `json:"separator,omitempty"`
+
+		File          string   `json:"file,omitempty"`
+		CPUOnlineFile string   `json:"cpu_online_file,omitempty"`
+		ShowAll       bool     `json:"show_all,omitempty"`
+		ShowProcs     bool     `json:"show_procs,omitempty"`
+		PerCPU        bool     `json:"per_cpu,omitempty"`
+		Trend         bool     `json:"trend,omitempty"`
+		TrendDelta    *float64 `json:"trend_delta,omitempty"`
+		Warning       float64  `json:"warning,omitempty"`
+		Critical      float64  `json:"critical,omitempty"`
+		WarningColor  string   `json:"warning_color,omitempty"`
+		CriticalColor string   `json:"critical_color,omitempty"`
 	} `json:"la,omitempty"`
 
 	// Mem plugin
@@ -115,6 +152,10 @@ type MyConfig struct {
 		FontSize   string    `json:"font_size,omitempty"`
 		ShowSwap   bool      `json:"show_swap,omitempty"`
 		Separator  Separator `json:"separator,omitempty"`
+
+		Format      string `json:"format,omitempty"`
+		ProcDir     string `json:"proc_dir,omitempty"`
+		SysBlockDir string `json:"sys_block_dir,omitempty"`
 	} `json:"mem,omitempty"`
 
 	Clock struct {
@@ -140,6 +181,12 @@ type MyConfig struct {
 		Enabled        bool     `json:"enabled,omitempty"`
 		UseSysfs       bool     `json:"use_sysfs,omitempty"`
 		SysfsFiles     []string `json:"sysfs_files,omitempty"`
+		SysfsRoot      string   `json:"sysfs_root,omitempty"`
+		Batteries      []string `json:"batteries,omitempty"`
+		IncludeDevices bool     `json:"include_devices,omitempty"`
+		ShowHealth     bool     `json:"show_health,omitempty"`
+		ShowPlug       bool     `json:"show_plug,omitempty"`
+		PlugSymbol     string   `json:"plug_symbol,omitempty"`
 		Color          string   `json:"color,omitempty"`
 		Background     string   `json:"background,omitempty"`
 		Font           string   `json:"font,omitempty"`
@@ -147,6 +194,39 @@ type MyConfig struct {
 		Symbol         string   `json:"symbol,omitempty"`
 		SymbolFont     string   `json:"symbol_font,omitempty"`
 		SymbolFontSize string   `json:"symbol_font_size,omitempty"`
+		ShowTime       bool     `json:"show_time,omitempty"`
+		ShowPower      bool     `json:"show_power,omitempty"`
+		RateSamples    int      `json:"rate_samples,omitempty"`
+		Aggregate      bool     `json:"aggregate,omitempty"`
+		CriticalLevel  int      `json:"critical_level,omitempty"`
+		Hysteresis     int      `json:"hysteresis,omitempty"`
+
+		Actions []struct {
+			Level int      `json:"level,omitempty"`
+			Cmd   []string `json:"cmd,omitempty"`
+		} `json:"actions,omitempty"`
+
+		ChargeLimit struct {
+			Show      bool     `json:"show,omitempty"`
+			Symbol    string   `json:"symbol,omitempty"`
+			Battery   string   `json:"battery,omitempty"`
+			Button    int      `json:"button,omitempty"`
+			HelperCmd []string `json:"helper_cmd,omitempty"`
+
+			Presets []struct {
+				Start int `json:"start,omitempty"`
+				End   int `json:"end,omitempty"`
+			} `json:"presets,omitempty"`
+		} `json:"charge_limit,omitempty"`
+
+		History struct {
+			Enabled        bool `json:"enabled,omitempty"`
+			Interval       int  `json:"interval,omitempty"`
+			MaxSize        int  `json:"max_size,omitempty"`
+			Sparkline      bool `json:"sparkline,omitempty"`
+			SparklineHours int  `json:"sparkline_hours,omitempty"`
+			SparklineWidth int  `json:"sparkline_width,omitempty"`
+		} `json:"history,omitempty"`
 
 		ChargeColor struct {
 			Full        string `json:"full,omitempty"`
@@ -166,7 +246,11 @@ type MyConfig struct {
 		FontSize   string    `json:"font_size,omitempty"`
 		Separator  Separator `json:"separator,omitempty"`
 
-		File []string `json:"file,omitempty"`
+		File      []string `json:"file,omitempty"`
+		HwmonRoot string   `json:"hwmon_root,omitempty"`
+		Chip      []string `json:"chip,omitempty"`
+		Labels    []string `json:"labels,omitempty"`
+		Aggregate string   `json:"aggregate,omitempty"`
 	} `json:"cpu_temp,omitempty"`
 
 	Vpn struct {
@@ -206,6 +290,28 @@ type MyConfig struct {
 		WheelUp        int      `json:"wheel_up,omitempty"`
 		WheelDown      int      `json:"wheel_down,omitempty"`
 		MaxVolumeLimit int      `json:"max_volume_limit,omitempty"`
+		MuteButton     int      `json:"mute_button,omitempty"`
+		MutedSymbol    string   `json:"muted_symbol,omitempty"`
+		MutedColor     string   `json:"muted_color,omitempty"`
+		LevelSymbols   []string `json:"level_symbols,omitempty"`
+
+		ShowOutput          bool              `json:"show_output,omitempty"`
+		OutputAliases       map[string]string `json:"output_aliases,omitempty"`
+		CycleOutputButton   int               `json:"cycle_output_button,omitempty"`
+		CycleOutputModifier string            `json:"cycle_output_modifier,omitempty"`
+		RestartServer       bool              `json:"restart_server,omitempty"`
+		UnavailableText     string            `json:"unavailable_text,omitempty"`
+
+		VolumeScale      string `json:"volume_scale,omitempty"`
+		FineStep         int    `json:"fine_step,omitempty"`
+		FineStepModifier string `json:"fine_step_modifier,omitempty"`
+		SinkLimits       []struct {
+			Sink  string `json:"sink,omitempty"`
+			Limit int    `json:"limit,omitempty"`
+
+			// SinkRe is compiled Sink regexp.
+			SinkRe *regexp.Regexp `json:"-"`
+		} `json:"sink_limits,omitempty"`
 	} `json:"simple-volume-pa,omitempty"`
 
 	NetIf struct {
@@ -246,6 +352,238 @@ type MyConfig struct {
 		Args       []string  `json:"args,omitempty"`
 	}
 
+	BindingMode struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Urgent     bool      `json:"urgent,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		Modes map[string]struct {
+			Color      string `json:"color,omitempty"`
+			Background string `json:"background,omitempty"`
+		} `json:"modes,omitempty"`
+	} `json:"binding_mode,omitempty"`
+
+	WindowTitle struct {
+		Enabled     bool      `json:"enabled,omitempty"`
+		Color       string    `json:"color,omitempty"`
+		Background  string    `json:"background,omitempty"`
+		Font        string    `json:"font,omitempty"`
+		FontSize    string    `json:"font_size,omitempty"`
+		MaxLength   int       `json:"max_length,omitempty"`
+		Ellipsis    string    `json:"ellipsis,omitempty"`
+		Privacy     []string  `json:"privacy,omitempty"`
+		PrivacyText string    `json:"privacy_text,omitempty"`
+		Separator   Separator `json:"separator,omitempty"`
+
+		Rewrite []struct {
+			Class   string `json:"class,omitempty"`
+			Match   string `json:"match,omitempty"`
+			Replace string `json:"replace,omitempty"`
+
+			// ClassRe and MatchRe are compiled Class and Match regexps, ClassRe is nil if Class is empty.
+			ClassRe *regexp.Regexp `json:"-"`
+			MatchRe *regexp.Regexp `json:"-"`
+		} `json:"rewrite,omitempty"`
+
+		// PrivacyRe are compiled Privacy regexps.
+		PrivacyRe []*regexp.Regexp `json:"-"`
+	} `json:"window_title,omitempty"`
+
+	Layout struct {
+		Enabled          bool              `json:"enabled,omitempty"`
+		Color            string            `json:"color,omitempty"`
+		Background       string            `json:"background,omitempty"`
+		Font             string            `json:"font,omitempty"`
+		FontSize         string            `json:"font_size,omitempty"`
+		Symbols          map[string]string `json:"symbols,omitempty"`
+		ScratchpadSymbol string            `json:"scratchpad_symbol,omitempty"`
+		LeftClick        string            `json:"left_click,omitempty"`
+		MiddleClick      string            `json:"middle_click,omitempty"`
+		RightClick       string            `json:"right_click,omitempty"`
+		WheelUp          string            `json:"wheel_up,omitempty"`
+		WheelDown        string            `json:"wheel_down,omitempty"`
+		Separator        Separator         `json:"separator,omitempty"`
+	} `json:"layout,omitempty"`
+
+	FocusTime struct {
+		Enabled       bool      `json:"enabled,omitempty"`
+		Color         string    `json:"color,omitempty"`
+		Background    string    `json:"background,omitempty"`
+		Font          string    `json:"font,omitempty"`
+		FontSize      string    `json:"font_size,omitempty"`
+		Symbol        string    `json:"symbol,omitempty"`
+		IdleCmd       []string  `json:"idle_cmd,omitempty"`
+		IdleFile      string    `json:"idle_file,omitempty"`
+		CheckInterval int       `json:"check_interval,omitempty"`
+		SaveInterval  int       `json:"save_interval,omitempty"`
+		KeepDays      int       `json:"keep_days,omitempty"`
+		ReportCmd     []string  `json:"report_cmd,omitempty"`
+		ReportTop     int       `json:"report_top,omitempty"`
+		Separator     Separator `json:"separator,omitempty"`
+	} `json:"focus_time,omitempty"`
+
+	MicVolumePa struct {
+		Enabled        bool      `json:"enabled,omitempty"`
+		Color          string    `json:"color,omitempty"`
+		Background     string    `json:"background,omitempty"`
+		Font           string    `json:"font,omitempty"`
+		FontSize       string    `json:"font_size,omitempty"`
+		Symbol         string    `json:"symbol,omitempty"`
+		SymbolFont     string    `json:"symbol_font,omitempty"`
+		SymbolFontSize string    `json:"symbol_font_size,omitempty"`
+		Separator      Separator `json:"separator,omitempty"`
+
+		Step           int      `json:"step,omitempty"`
+		RightClickCmd  []string `json:"right_click_cmd,omitempty"`
+		WheelUp        int      `json:"wheel_up,omitempty"`
+		WheelDown      int      `json:"wheel_down,omitempty"`
+		MaxVolumeLimit int      `json:"max_volume_limit,omitempty"`
+		MuteButton     int      `json:"mute_button,omitempty"`
+		MutedSymbol    string   `json:"muted_symbol,omitempty"`
+		MutedColor     string   `json:"muted_color,omitempty"`
+		InUseColor     string   `json:"in_use_color,omitempty"`
+		InUseUrgent    bool     `json:"in_use_urgent,omitempty"`
+		ShowApps       bool     `json:"show_apps,omitempty"`
+		IgnoreApps     []string `json:"ignore_apps,omitempty"`
+
+		UnavailableText string `json:"unavailable_text,omitempty"`
+	} `json:"mic-volume-pa,omitempty"`
+
+	StreamVolumePa struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Symbol     string    `json:"symbol,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		Step           int      `json:"step,omitempty"`
+		WheelUp        int      `json:"wheel_up,omitempty"`
+		WheelDown      int      `json:"wheel_down,omitempty"`
+		MaxVolumeLimit int      `json:"max_volume_limit,omitempty"`
+		NextButton     int      `json:"next_button,omitempty"`
+		MuteButton     int      `json:"mute_button,omitempty"`
+		MutedColor     string   `json:"muted_color,omitempty"`
+		MaxNameLength  int      `json:"max_name_length,omitempty"`
+		HideIdle       bool     `json:"hide_idle,omitempty"`
+		ShowPaused     bool     `json:"show_paused,omitempty"`
+		IgnoreApps     []string `json:"ignore_apps,omitempty"`
+	} `json:"stream-volume-pa,omitempty"`
+
+	CardProfile struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Symbol     string    `json:"symbol,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		Card            string            `json:"card,omitempty"`
+		Profiles        []string          `json:"profiles,omitempty"`
+		Aliases         map[string]string `json:"aliases,omitempty"`
+		NextButton      int               `json:"next_button,omitempty"`
+		PrevButton      int               `json:"prev_button,omitempty"`
+		WheelUp         int               `json:"wheel_up,omitempty"`
+		WheelDown       int               `json:"wheel_down,omitempty"`
+		HideMissing     bool              `json:"hide_missing,omitempty"`
+		MissingText     string            `json:"missing_text,omitempty"`
+		UnavailableText string            `json:"unavailable_text,omitempty"`
+
+		// CardRe is compiled Card regexp.
+		CardRe *regexp.Regexp `json:"-"`
+	} `json:"card-profile,omitempty"`
+
+	Sensors struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		HwmonRoot     string `json:"hwmon_root,omitempty"`
+		Interval      int    `json:"interval,omitempty"`
+		Unit          string `json:"unit,omitempty"`
+		WarningColor  string `json:"warning_color,omitempty"`
+		CriticalColor string `json:"critical_color,omitempty"`
+
+		Items []struct {
+			Label     string   `json:"label,omitempty"`
+			Chip      []string `json:"chip,omitempty"`
+			Sensors   []string `json:"sensors,omitempty"`
+			Kind      string   `json:"kind,omitempty"`
+			Aggregate string   `json:"aggregate,omitempty"`
+			Warning   float64  `json:"warning,omitempty"`
+			Critical  float64  `json:"critical,omitempty"`
+		} `json:"items,omitempty"`
+	} `json:"sensors,omitempty"`
+
+	CPUUsage struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		StatFile   string `json:"stat_file,omitempty"`
+		Interval   int    `json:"interval,omitempty"`
+		ShowIOWait bool   `json:"show_iowait,omitempty"`
+		PerCore    bool   `json:"per_core,omitempty"`
+	} `json:"cpu_usage,omitempty"`
+
+	CPUFreq struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		SysfsRoot string   `json:"sysfs_root,omitempty"`
+		Interval  int      `json:"interval,omitempty"`
+		Show      string   `json:"show,omitempty"`
+		HelperCmd []string `json:"helper_cmd,omitempty"`
+
+		Profiles []struct {
+			Button   int    `json:"button,omitempty"`
+			Governor string `json:"governor,omitempty"`
+			EPP      string `json:"epp,omitempty"`
+		} `json:"profiles,omitempty"`
+	} `json:"cpu_freq,omitempty"`
+
+	PSI struct {
+		Enabled    bool      `json:"enabled,omitempty"`
+		Color      string    `json:"color,omitempty"`
+		Background string    `json:"background,omitempty"`
+		Font       string    `json:"font,omitempty"`
+		FontSize   string    `json:"font_size,omitempty"`
+		Separator  Separator `json:"separator,omitempty"`
+
+		ProcDir       string   `json:"proc_dir,omitempty"`
+		Resources     []string `json:"resources,omitempty"`
+		Interval      int      `json:"interval,omitempty"`
+		ShowFull      bool     `json:"show_full,omitempty"`
+		Warning       float64  `json:"warning,omitempty"`
+		Critical      float64  `json:"critical,omitempty"`
+		WarningColor  string   `json:"warning_color,omitempty"`
+		CriticalColor string   `json:"critical_color,omitempty"`
+
+		Hook struct {
+			Resource  string   `json:"resource,omitempty"`
+			Kind      string   `json:"kind,omitempty"`
+			Threshold float64  `json:"threshold,omitempty"`
+			Duration  int      `json:"duration,omitempty"`
+			Cmd       []string `json:"cmd,omitempty"`
+		} `json:"hook,omitempty"`
+	} `json:"psi,omitempty"`
+
 	AppButtons struct {
 		Enabled    bool      `json:"enabled,omitempty"`
 		Color      string    `json:"color,omitempty"`
@@ -270,6 +608,9 @@ type MyConfig struct {
 		BorderActive        string   `json:"border_active,omitempty"`
 		Separator           bool     `json:"separator,omitempty"`
 		SeparatorBlockWidth int      `json:"separator_block_width,omitempty"`
+		I3Exec              bool     `json:"i3_exec,omitempty"`
+		Workspace           string   `json:"workspace,omitempty"`
+		StartupID           bool     `json:"startup_id,omitempty"`
 	} `json:"apps,omitempty"`
 }
 
@@ -458,6 +799,33 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 		sampleConfig.Separator.Right.FontSize = sampleConfig.FontSize
 	}
 
+	// sampleConfig.LA.ShowAll will false if not set in config
+	// sampleConfig.LA.ShowProcs will false if not set in config
+	// sampleConfig.LA.PerCPU will false if not set in config
+	// sampleConfig.LA.Trend will false if not set in config
+	// sampleConfig.LA.Warning and sampleConfig.LA.Critical are not checked if not set in config
+	if sampleConfig.LA.File == "" {
+		sampleConfig.LA.File = "/proc/loadavg"
+	}
+
+	if sampleConfig.LA.CPUOnlineFile == "" {
+		sampleConfig.LA.CPUOnlineFile = "/sys/devices/system/cpu/online"
+	}
+
+	// Zero delta is allowed, then any difference between 1 and 15 minutes load average is shown as trend
+	if sampleConfig.LA.TrendDelta == nil || *sampleConfig.LA.TrendDelta < 0 {
+		delta := 0.1
+		sampleConfig.LA.TrendDelta = &delta
+	}
+
+	if sampleConfig.LA.WarningColor == "" {
+		sampleConfig.LA.WarningColor = "#ffaa00"
+	}
+
+	if sampleConfig.LA.CriticalColor == "" {
+		sampleConfig.LA.CriticalColor = "#ff5555"
+	}
+
 	if sampleConfig.LA.Color == "" {
 		sampleConfig.LA.Color = sampleConfig.Color
 	}
@@ -586,6 +954,23 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 		}
 	}
 
+	// sampleConfig.Mem.ShowSwap will false if not set in config, it is used only for default format
+	if sampleConfig.Mem.Format == "" {
+		sampleConfig.Mem.Format = "M:{used_pct}% SHM:{shared}"
+
+		if sampleConfig.Mem.ShowSwap {
+			sampleConfig.Mem.Format += " SW:{swap}"
+		}
+	}
+
+	if sampleConfig.Mem.ProcDir == "" {
+		sampleConfig.Mem.ProcDir = "/proc"
+	}
+
+	if sampleConfig.Mem.SysBlockDir == "" {
+		sampleConfig.Mem.SysBlockDir = "/sys/block"
+	}
+
 	if sampleConfig.Mem.Color == "" {
 		sampleConfig.Mem.Color = sampleConfig.Color
 	}
@@ -858,12 +1243,79 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 
 	// sampleConfig.Battery.Enabled will be false if not set in config
 	// sampleConfig.Battery.UseSysfs will be false if not set in config
+	// sampleConfig.Battery.ShowTime will be false if not set in config
+	// sampleConfig.Battery.ShowPower will be false if not set in config
+	// sampleConfig.Battery.Aggregate will be false if not set in config
+	// sampleConfig.Battery.CriticalLevel will be 0 (urgent flag only on empty battery) if not set in config
+	if sampleConfig.Battery.Hysteresis <= 0 {
+		sampleConfig.Battery.Hysteresis = 2
+	}
 
-	if len(sampleConfig.Battery.SysfsFiles) == 0 {
-		sampleConfig.Battery.SysfsFiles = append(
-			sampleConfig.Battery.SysfsFiles,
-			"/sys/class/power_supply/BAT0/capacity",
-		)
+	for i, action := range sampleConfig.Battery.Actions {
+		if len(action.Cmd) == 0 || action.Cmd[0] == "" {
+			log.Printf("sampleConfig.Battery.Actions[%d].Cmd is empty, action disabled", i)
+
+			sampleConfig.Battery.Actions[i].Cmd = []string{"true"}
+		}
+	}
+
+	if sampleConfig.Battery.RateSamples <= 0 {
+		sampleConfig.Battery.RateSamples = 12
+	}
+
+	// sampleConfig.Battery.Batteries can be empty, in that case all system batteries are shown
+	// sampleConfig.Battery.IncludeDevices will be false if not set in config
+	// sampleConfig.Battery.ShowHealth will be false if not set in config
+	// sampleConfig.Battery.ShowPlug will be false if not set in config
+	if sampleConfig.Battery.SysfsRoot == "" {
+		sampleConfig.Battery.SysfsRoot = "/sys/class/power_supply"
+	}
+
+	// Obsolete sysfs_files pointed to capacity files, now they only select batteries by name.
+	for _, file := range sampleConfig.Battery.SysfsFiles {
+		sampleConfig.Battery.Batteries = append(sampleConfig.Battery.Batteries, filepath.Base(filepath.Dir(file)))
+	}
+
+	if sampleConfig.Battery.PlugSymbol == "" {
+		sampleConfig.Battery.PlugSymbol = `🔌`
+	}
+
+	// sampleConfig.Battery.ChargeLimit.Show will be false if not set in config
+	// sampleConfig.Battery.ChargeLimit.Battery can be empty, in that case first battery with thresholds is used
+	// sampleConfig.Battery.ChargeLimit.HelperCmd can be empty, in that case only direct writes to sysfs are tried
+	if sampleConfig.Battery.ChargeLimit.Symbol == "" {
+		sampleConfig.Battery.ChargeLimit.Symbol = "≤"
+	}
+
+	if sampleConfig.Battery.ChargeLimit.Button == 0 {
+		sampleConfig.Battery.ChargeLimit.Button = 3
+	}
+
+	// sampleConfig.Battery.History.Enabled will be false if not set in config
+	// sampleConfig.Battery.History.Sparkline will be false if not set in config
+	if sampleConfig.Battery.History.Interval <= 0 {
+		sampleConfig.Battery.History.Interval = 60
+	}
+
+	if sampleConfig.Battery.History.MaxSize <= 0 {
+		sampleConfig.Battery.History.MaxSize = 1024
+	}
+
+	if sampleConfig.Battery.History.SparklineHours <= 0 {
+		sampleConfig.Battery.History.SparklineHours = 6
+	}
+
+	if sampleConfig.Battery.History.SparklineWidth <= 0 {
+		sampleConfig.Battery.History.SparklineWidth = 12
+	}
+
+	for i, p := range sampleConfig.Battery.ChargeLimit.Presets {
+		if p.End <= 0 || p.End > 100 || p.Start < 0 || p.Start >= p.End {
+			log.Printf("sampleConfig.Battery.ChargeLimit.Presets[%d] is invalid, fallback to 100%%", i)
+
+			sampleConfig.Battery.ChargeLimit.Presets[i].Start = 0
+			sampleConfig.Battery.ChargeLimit.Presets[i].End = 100
+		}
 	}
 
 	if sampleConfig.Battery.Color == "" {
@@ -1037,10 +1489,29 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 	// sampleConfig.CpuTemp.Enabled will be false if not set in config
 
 	// No files configured - disable plugin
-	if len(sampleConfig.CPUTemp.File) == 0 {
+	if len(sampleConfig.CPUTemp.File) == 0 && len(sampleConfig.CPUTemp.Chip) == 0 {
 		sampleConfig.CPUTemp.Enabled = false
 	}
 
+	// sampleConfig.CPUTemp.Labels can be empty, in that case all temperature inputs of chip are used
+	if sampleConfig.CPUTemp.HwmonRoot == "" {
+		sampleConfig.CPUTemp.HwmonRoot = "/sys/class/hwmon"
+	}
+
+	for _, chip := range sampleConfig.CPUTemp.Chip {
+		if _, err := filepath.Match(chip, ""); err != nil {
+			log.Printf("Unable to use sampleConfig.CPUTemp.Chip glob %s, disabling cpu_temp: %s", chip, err)
+
+			sampleConfig.CPUTemp.Enabled = false
+		}
+	}
+
+	switch sampleConfig.CPUTemp.Aggregate {
+	case "avg", "max", "all":
+	default:
+		sampleConfig.CPUTemp.Aggregate = "avg"
+	}
+
 	if sampleConfig.CPUTemp.Color == "" {
 		sampleConfig.CPUTemp.Color = sampleConfig.Color
 	}
@@ -1444,6 +1915,65 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 		sampleConfig.SimpleVolumePa.MaxVolumeLimit = 100
 	}
 
+	if sampleConfig.SimpleVolumePa.MuteButton == 0 {
+		sampleConfig.SimpleVolumePa.MuteButton = 2
+	}
+
+	if sampleConfig.SimpleVolumePa.MutedSymbol == "" {
+		sampleConfig.SimpleVolumePa.MutedSymbol = `🔇`
+	}
+
+	if sampleConfig.SimpleVolumePa.MutedColor == "" {
+		sampleConfig.SimpleVolumePa.MutedColor = "#666666"
+	}
+
+	// sampleConfig.SimpleVolumePa.LevelSymbols can be empty, in that case sampleConfig.SimpleVolumePa.Symbol is used
+	// sampleConfig.SimpleVolumePa.ShowOutput will be false if not set in config
+	// sampleConfig.SimpleVolumePa.OutputAliases can be empty, in that case port descriptions are shown as is
+	// sampleConfig.SimpleVolumePa.CycleOutputButton will be 0 (disabled) if not set in config
+	// sampleConfig.SimpleVolumePa.CycleOutputModifier can be empty, in that case wheel does not cycle outputs
+	// sampleConfig.SimpleVolumePa.RestartServer will be false if not set in config
+	if sampleConfig.SimpleVolumePa.UnavailableText == "" {
+		sampleConfig.SimpleVolumePa.UnavailableText = "n/a"
+	}
+
+	// Pulseaudio volume is already cubic, so "cubic" scale is the same as "linear" one.
+	if sampleConfig.SimpleVolumePa.VolumeScale != "db" {
+		sampleConfig.SimpleVolumePa.VolumeScale = "linear"
+	}
+
+	if sampleConfig.SimpleVolumePa.FineStep <= 0 {
+		sampleConfig.SimpleVolumePa.FineStep = 1
+	}
+
+	if sampleConfig.SimpleVolumePa.FineStepModifier == "" {
+		sampleConfig.SimpleVolumePa.FineStepModifier = "Shift"
+	}
+
+	sinkLimits := sampleConfig.SimpleVolumePa.SinkLimits[:0]
+
+	for i, l := range sampleConfig.SimpleVolumePa.SinkLimits {
+		re, err := regexp.Compile(l.Sink)
+
+		if err != nil {
+			log.Printf(
+				"Unable to compile sampleConfig.SimpleVolumePa.SinkLimits[%d].Sink regexp, entry disabled: %s", i, err,
+			)
+
+			continue
+		}
+
+		l.SinkRe = re
+
+		if l.Limit <= 0 || l.Limit >= 150 {
+			l.Limit = sampleConfig.SimpleVolumePa.MaxVolumeLimit
+		}
+
+		sinkLimits = append(sinkLimits, l)
+	}
+
+	sampleConfig.SimpleVolumePa.SinkLimits = sinkLimits
+
 	if len(sampleConfig.SimpleVolumePa.RightClickCmd) > 0 {
 		if sampleConfig.SimpleVolumePa.RightClickCmd[0] == "" {
 			sampleConfig.SimpleVolumePa.RightClickCmd[0] = "true"
@@ -1830,196 +2360,2027 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 		}
 	}
 
-	// sampleConfig.AppButtons.Enabled will false if not set in config
-	if sampleConfig.AppButtons.Color == "" {
-		sampleConfig.AppButtons.Color = sampleConfig.Color
+	// sampleConfig.BindingMode.Enabled will false if not set in config
+	// sampleConfig.BindingMode.Urgent will false if not set in config
+	// sampleConfig.BindingMode.Modes can be empty, in that case block colors are used for all modes
+
+	if sampleConfig.BindingMode.Color == "" {
+		sampleConfig.BindingMode.Color = sampleConfig.Color
 	}
 
-	if sampleConfig.AppButtons.Background == "" {
-		sampleConfig.AppButtons.Background = sampleConfig.Background
+	if sampleConfig.BindingMode.Background == "" {
+		sampleConfig.BindingMode.Background = sampleConfig.Background
 	}
 
-	if sampleConfig.AppButtons.Font == "" {
-		sampleConfig.AppButtons.Font = sampleConfig.Font
+	if sampleConfig.BindingMode.Font == "" {
+		sampleConfig.BindingMode.Font = sampleConfig.Font
 	}
 
-	if sampleConfig.AppButtons.FontSize == "" {
-		sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+	if sampleConfig.BindingMode.FontSize == "" {
+		sampleConfig.BindingMode.FontSize = sampleConfig.FontSize
 	} else {
 		matched, err := regexp.MatchString(
 			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
-			sampleConfig.AppButtons.FontSize,
+			sampleConfig.BindingMode.FontSize,
 		)
 
 		if err != nil {
 			log.Printf(
-				"Unable to set sampleConfig.AppButtons.FontSize: %s, fallback to %s",
+				"Unable to set sampleConfig.BindingMode.FontSize: %s, fallback to %s",
 				err,
 				sampleConfig.FontSize,
 			)
 
-			sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+			sampleConfig.BindingMode.FontSize = sampleConfig.FontSize
 		}
 
 		if !matched {
 			log.Printf(
-				"Unable to set sampleConfig.AppButtons.FontSize, fallback to %s",
+				"Unable to set sampleConfig.BindingMode.FontSize, fallback to %s",
 				sampleConfig.FontSize,
 			)
 
-			sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+			sampleConfig.BindingMode.FontSize = sampleConfig.FontSize
 		}
 	}
 
-	if sampleConfig.AppButtons.Separator.Left.Color == "" {
-		sampleConfig.AppButtons.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	if sampleConfig.BindingMode.Separator.Left.Color == "" {
+		sampleConfig.BindingMode.Separator.Left.Color = sampleConfig.Separator.Left.Color
 	}
 
-	if sampleConfig.AppButtons.Separator.Left.Background == "" {
-		sampleConfig.AppButtons.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	if sampleConfig.BindingMode.Separator.Left.Background == "" {
+		sampleConfig.BindingMode.Separator.Left.Background = sampleConfig.Separator.Left.Background
 	}
 
-	if sampleConfig.AppButtons.Separator.Left.Symbol == "" {
-		sampleConfig.AppButtons.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	if sampleConfig.BindingMode.Separator.Left.Symbol == "" {
+		sampleConfig.BindingMode.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
 	}
 
-	if sampleConfig.AppButtons.Separator.Left.Font == "" {
-		sampleConfig.AppButtons.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	if sampleConfig.BindingMode.Separator.Left.Font == "" {
+		sampleConfig.BindingMode.Separator.Left.Font = sampleConfig.Separator.Left.Font
 	}
 
-	if sampleConfig.AppButtons.Separator.Left.FontSize == "" {
-		sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	if sampleConfig.BindingMode.Separator.Left.FontSize == "" {
+		sampleConfig.BindingMode.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
 	} else {
 		matched, err := regexp.MatchString(
 			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
-			sampleConfig.AppButtons.Separator.Left.FontSize,
+			sampleConfig.BindingMode.Separator.Left.FontSize,
 		)
 
 		if err != nil {
 			log.Printf(
-				"Unable to set sampleConfig.AppButtons.Separator.Left.FontSize: %s, fallback to %s",
+				"Unable to set sampleConfig.BindingMode.Separator.Left.FontSize: %s, fallback to %s",
 				err,
 				sampleConfig.Separator.Left.FontSize,
 			)
 
-			sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+			sampleConfig.BindingMode.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
 		}
 
 		if !matched {
 			log.Printf(
-				"Unable to set sampleConfig.AppButtons.Separator.Left.FontSize, fallback to %s",
+				"Unable to set sampleConfig.BindingMode.Separator.Left.FontSize, fallback to %s",
 				sampleConfig.Separator.Left.FontSize,
 			)
 
-			sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+			sampleConfig.BindingMode.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
 		}
 	}
 
-	if sampleConfig.AppButtons.Separator.Right.Color == "" {
-		sampleConfig.AppButtons.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	if sampleConfig.BindingMode.Separator.Right.Color == "" {
+		sampleConfig.BindingMode.Separator.Right.Color = sampleConfig.Separator.Right.Color
 	}
 
-	if sampleConfig.AppButtons.Separator.Right.Background == "" {
-		sampleConfig.AppButtons.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	if sampleConfig.BindingMode.Separator.Right.Background == "" {
+		sampleConfig.BindingMode.Separator.Right.Background = sampleConfig.Separator.Right.Background
 	}
 
-	if sampleConfig.AppButtons.Separator.Right.Symbol == "" {
-		sampleConfig.AppButtons.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	if sampleConfig.BindingMode.Separator.Right.Symbol == "" {
+		sampleConfig.BindingMode.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
 	}
 
-	if sampleConfig.AppButtons.Separator.Right.Font == "" {
-		sampleConfig.AppButtons.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	if sampleConfig.BindingMode.Separator.Right.Font == "" {
+		sampleConfig.BindingMode.Separator.Right.Font = sampleConfig.Separator.Right.Font
 	}
 
-	if sampleConfig.AppButtons.Separator.Right.FontSize == "" {
-		sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	if sampleConfig.BindingMode.Separator.Right.FontSize == "" {
+		sampleConfig.BindingMode.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
 	} else {
 		matched, err := regexp.MatchString(
 			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
-			sampleConfig.AppButtons.Separator.Right.FontSize,
+			sampleConfig.BindingMode.Separator.Right.FontSize,
 		)
 
 		if err != nil {
 			log.Printf(
-				"Unable to set sampleConfig.AppButtons.Separator.Right.FontSize: %s, fallback to %s",
+				"Unable to set sampleConfig.BindingMode.Separator.Right.FontSize: %s, fallback to %s",
 				err,
 				sampleConfig.Separator.Right.FontSize,
 			)
 
-			sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+			sampleConfig.BindingMode.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
 		}
 
 		if !matched {
-			log.Printf("Unable to set sampleConfig.AppButtons.Separator.Right.FontSize, fallback to %s",
+			log.Printf(
+				"Unable to set sampleConfig.BindingMode.Separator.Right.FontSize, fallback to %s",
 				sampleConfig.Separator.Right.FontSize,
 			)
 
-			sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+			sampleConfig.BindingMode.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
 		}
 	}
 
-	if len(sampleConfig.Apps) == 0 {
-		sampleConfig.AppButtons.Enabled = false
-	} else {
-		for num, app := range sampleConfig.Apps {
-			// app.Instance can be missing.
-			// app.Class can be missing.
-			if app.Name == "" {
-				sampleConfig.Apps[num].Name = fmt.Sprintf("app%d", num)
-			}
+	// sampleConfig.WindowTitle.Enabled will false if not set in config
+	// sampleConfig.WindowTitle.Privacy can be empty
+	// sampleConfig.WindowTitle.Rewrite can be empty
+	if sampleConfig.WindowTitle.MaxLength <= 0 {
+		sampleConfig.WindowTitle.MaxLength = 60
+	}
 
-			// app.Args can be empty slice. In that case command will be run without aruments.
-			if app.Cmd == "" {
-				// If command omitted it will be just /usr/bin/true.
-				sampleConfig.Apps[num].Cmd = "true"
-			}
+	if sampleConfig.WindowTitle.Ellipsis == "" {
+		sampleConfig.WindowTitle.Ellipsis = "…"
+	}
 
-			if app.Color == "" {
-				sampleConfig.Apps[num].Color = sampleConfig.AppButtons.Color
-			}
+	if sampleConfig.WindowTitle.PrivacyText == "" {
+		sampleConfig.WindowTitle.PrivacyText = "🔒"
+	}
 
-			if app.Background == "" {
-				sampleConfig.Apps[num].Background = sampleConfig.AppButtons.Background
-			}
+	for i, pattern := range sampleConfig.WindowTitle.Privacy {
+		re, err := regexp.Compile(pattern)
 
-			if app.Font == "" {
-				sampleConfig.Apps[num].Font = sampleConfig.AppButtons.Font
-			}
+		if err != nil {
+			log.Printf("Unable to compile sampleConfig.WindowTitle.Privacy[%d] regexp, pattern disabled: %s", i, err)
 
-			if app.FontSize == "" {
-				sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
-			} else {
-				matched, err := regexp.MatchString(
-					`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
-					app.FontSize,
-				)
+			continue
+		}
 
-				if err != nil {
-					log.Printf(
-						"Unable to set %s.FontSize: %s, fallback to %s",
-						app.Name,
-						err,
-						sampleConfig.AppButtons.FontSize,
-					)
+		sampleConfig.WindowTitle.PrivacyRe = append(sampleConfig.WindowTitle.PrivacyRe, re)
+	}
 
-					sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
-				}
+	rewrite := sampleConfig.WindowTitle.Rewrite[:0]
 
-				if !matched {
-					log.Printf("Unable to set %s.FontSize, fallback to %s",
-						app.Name,
-						sampleConfig.AppButtons.FontSize,
-					)
+	for i, rule := range sampleConfig.WindowTitle.Rewrite {
+		var err error
 
-					sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
-				}
-			}
+		if rule.Class != "" {
+			if rule.ClassRe, err = regexp.Compile(rule.Class); err != nil {
+				log.Printf("Unable to compile sampleConfig.WindowTitle.Rewrite[%d].Class regexp, rule disabled: %s", i, err)
 
-			if app.Border == "" {
-				sampleConfig.Apps[num].Border = sampleConfig.AppButtons.Color
+				continue
 			}
+		}
 
-			if app.BorderActive == "" {
+		if rule.MatchRe, err = regexp.Compile(rule.Match); err != nil {
+			log.Printf("Unable to compile sampleConfig.WindowTitle.Rewrite[%d].Match regexp, rule disabled: %s", i, err)
+
+			continue
+		}
+
+		rewrite = append(rewrite, rule)
+	}
+
+	sampleConfig.WindowTitle.Rewrite = rewrite
+
+	if sampleConfig.WindowTitle.Color == "" {
+		sampleConfig.WindowTitle.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.WindowTitle.Background == "" {
+		sampleConfig.WindowTitle.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.WindowTitle.Font == "" {
+		sampleConfig.WindowTitle.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.WindowTitle.FontSize == "" {
+		sampleConfig.WindowTitle.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.WindowTitle.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.WindowTitle.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.WindowTitle.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.WindowTitle.Separator.Left.Color == "" {
+		sampleConfig.WindowTitle.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.WindowTitle.Separator.Left.Background == "" {
+		sampleConfig.WindowTitle.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.WindowTitle.Separator.Left.Symbol == "" {
+		sampleConfig.WindowTitle.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.WindowTitle.Separator.Left.Font == "" {
+		sampleConfig.WindowTitle.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.WindowTitle.Separator.Left.FontSize == "" {
+		sampleConfig.WindowTitle.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.WindowTitle.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.WindowTitle.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.WindowTitle.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.WindowTitle.Separator.Right.Color == "" {
+		sampleConfig.WindowTitle.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.WindowTitle.Separator.Right.Background == "" {
+		sampleConfig.WindowTitle.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.WindowTitle.Separator.Right.Symbol == "" {
+		sampleConfig.WindowTitle.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.WindowTitle.Separator.Right.Font == "" {
+		sampleConfig.WindowTitle.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.WindowTitle.Separator.Right.FontSize == "" {
+		sampleConfig.WindowTitle.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.WindowTitle.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.WindowTitle.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.WindowTitle.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.WindowTitle.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.Layout.Enabled will false if not set in config
+	// sampleConfig.Layout.MiddleClick can be empty, then middle click does nothing
+	if sampleConfig.Layout.Symbols == nil {
+		sampleConfig.Layout.Symbols = map[string]string{}
+	}
+
+	for layout, symbol := range map[string]string{
+		"splith":   "[H]",
+		"splitv":   "[V]",
+		"tabbed":   "[T]",
+		"stacked":  "[S]",
+		"floating": "[F]",
+	} {
+		if sampleConfig.Layout.Symbols[layout] == "" {
+			sampleConfig.Layout.Symbols[layout] = symbol
+		}
+	}
+
+	if sampleConfig.Layout.ScratchpadSymbol == "" {
+		sampleConfig.Layout.ScratchpadSymbol = "⧉"
+	}
+
+	if sampleConfig.Layout.LeftClick == "" {
+		sampleConfig.Layout.LeftClick = "layout toggle all"
+	}
+
+	if sampleConfig.Layout.RightClick == "" {
+		sampleConfig.Layout.RightClick = "scratchpad show"
+	}
+
+	if sampleConfig.Layout.WheelUp == "" {
+		sampleConfig.Layout.WheelUp = "layout toggle all"
+	}
+
+	if sampleConfig.Layout.WheelDown == "" {
+		sampleConfig.Layout.WheelDown = "layout toggle split"
+	}
+
+	if sampleConfig.Layout.Color == "" {
+		sampleConfig.Layout.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.Layout.Background == "" {
+		sampleConfig.Layout.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.Layout.Font == "" {
+		sampleConfig.Layout.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.Layout.FontSize == "" {
+		sampleConfig.Layout.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Layout.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.Layout.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.Layout.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.Layout.Separator.Left.Color == "" {
+		sampleConfig.Layout.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.Layout.Separator.Left.Background == "" {
+		sampleConfig.Layout.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.Layout.Separator.Left.Symbol == "" {
+		sampleConfig.Layout.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.Layout.Separator.Left.Font == "" {
+		sampleConfig.Layout.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.Layout.Separator.Left.FontSize == "" {
+		sampleConfig.Layout.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Layout.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.Layout.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.Layout.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.Layout.Separator.Right.Color == "" {
+		sampleConfig.Layout.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.Layout.Separator.Right.Background == "" {
+		sampleConfig.Layout.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.Layout.Separator.Right.Symbol == "" {
+		sampleConfig.Layout.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.Layout.Separator.Right.Font == "" {
+		sampleConfig.Layout.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.Layout.Separator.Right.FontSize == "" {
+		sampleConfig.Layout.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Layout.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.Layout.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Layout.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.Layout.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.FocusTime.Enabled will false if not set in config
+	// sampleConfig.FocusTime.IdleCmd can be empty
+	// sampleConfig.FocusTime.IdleFile can be empty
+	if sampleConfig.FocusTime.Symbol == "" {
+		sampleConfig.FocusTime.Symbol = "⏱"
+	}
+
+	if sampleConfig.FocusTime.CheckInterval <= 0 {
+		sampleConfig.FocusTime.CheckInterval = 10
+	}
+
+	if sampleConfig.FocusTime.SaveInterval <= 0 {
+		sampleConfig.FocusTime.SaveInterval = 60
+	}
+
+	if sampleConfig.FocusTime.KeepDays <= 0 {
+		sampleConfig.FocusTime.KeepDays = 30
+	}
+
+	if len(sampleConfig.FocusTime.ReportCmd) == 0 || sampleConfig.FocusTime.ReportCmd[0] == "" {
+		sampleConfig.FocusTime.ReportCmd = []string{"notify-send", "Focus time today"}
+	}
+
+	if sampleConfig.FocusTime.ReportTop <= 0 {
+		sampleConfig.FocusTime.ReportTop = 10
+	}
+
+	if sampleConfig.FocusTime.Color == "" {
+		sampleConfig.FocusTime.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.FocusTime.Background == "" {
+		sampleConfig.FocusTime.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.FocusTime.Font == "" {
+		sampleConfig.FocusTime.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.FocusTime.FontSize == "" {
+		sampleConfig.FocusTime.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.FocusTime.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.FocusTime.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.FocusTime.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.FocusTime.Separator.Left.Color == "" {
+		sampleConfig.FocusTime.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.FocusTime.Separator.Left.Background == "" {
+		sampleConfig.FocusTime.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.FocusTime.Separator.Left.Symbol == "" {
+		sampleConfig.FocusTime.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.FocusTime.Separator.Left.Font == "" {
+		sampleConfig.FocusTime.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.FocusTime.Separator.Left.FontSize == "" {
+		sampleConfig.FocusTime.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.FocusTime.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.FocusTime.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.FocusTime.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.FocusTime.Separator.Right.Color == "" {
+		sampleConfig.FocusTime.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.FocusTime.Separator.Right.Background == "" {
+		sampleConfig.FocusTime.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.FocusTime.Separator.Right.Symbol == "" {
+		sampleConfig.FocusTime.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.FocusTime.Separator.Right.Font == "" {
+		sampleConfig.FocusTime.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.FocusTime.Separator.Right.FontSize == "" {
+		sampleConfig.FocusTime.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.FocusTime.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.FocusTime.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.FocusTime.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.FocusTime.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.MicVolumePa.Enabled will false if not set in config
+	// sampleConfig.MicVolumePa.InUseUrgent will false if not set in config
+	// sampleConfig.MicVolumePa.ShowApps will false if not set in config
+	if sampleConfig.MicVolumePa.Symbol == "" {
+		sampleConfig.MicVolumePa.Symbol = `🎤`
+	}
+
+	if sampleConfig.MicVolumePa.Step == 0 {
+		sampleConfig.MicVolumePa.Step = 5
+	}
+
+	if sampleConfig.MicVolumePa.WheelUp == 0 {
+		sampleConfig.MicVolumePa.WheelUp = 4
+	}
+
+	if sampleConfig.MicVolumePa.WheelDown == 0 {
+		sampleConfig.MicVolumePa.WheelDown = 5
+	}
+
+	if sampleConfig.MicVolumePa.MaxVolumeLimit <= 0 || sampleConfig.MicVolumePa.MaxVolumeLimit >= 150 {
+		sampleConfig.MicVolumePa.MaxVolumeLimit = 100
+	}
+
+	if sampleConfig.MicVolumePa.MuteButton == 0 {
+		sampleConfig.MicVolumePa.MuteButton = 1
+	}
+
+	if sampleConfig.MicVolumePa.MutedSymbol == "" {
+		sampleConfig.MicVolumePa.MutedSymbol = `🎙`
+	}
+
+	if sampleConfig.MicVolumePa.MutedColor == "" {
+		sampleConfig.MicVolumePa.MutedColor = "#666666"
+	}
+
+	if sampleConfig.MicVolumePa.InUseColor == "" {
+		sampleConfig.MicVolumePa.InUseColor = "#ff5555"
+	}
+
+	if sampleConfig.MicVolumePa.IgnoreApps == nil {
+		sampleConfig.MicVolumePa.IgnoreApps = []string{"PulseAudio Volume Control", "org.PulseAudio.pavucontrol"}
+	}
+
+	if sampleConfig.MicVolumePa.UnavailableText == "" {
+		sampleConfig.MicVolumePa.UnavailableText = "n/a"
+	}
+
+	if len(sampleConfig.MicVolumePa.RightClickCmd) == 0 || sampleConfig.MicVolumePa.RightClickCmd[0] == "" {
+		sampleConfig.MicVolumePa.RightClickCmd = []string{"true"}
+	}
+
+	if sampleConfig.MicVolumePa.Color == "" {
+		sampleConfig.MicVolumePa.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.MicVolumePa.Background == "" {
+		sampleConfig.MicVolumePa.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.MicVolumePa.Font == "" {
+		sampleConfig.MicVolumePa.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.MicVolumePa.FontSize == "" {
+		sampleConfig.MicVolumePa.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.MicVolumePa.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.MicVolumePa.SymbolFont == "" {
+		sampleConfig.MicVolumePa.SymbolFont = sampleConfig.MicVolumePa.Font
+	}
+
+	if sampleConfig.MicVolumePa.SymbolFontSize == "" {
+		sampleConfig.MicVolumePa.SymbolFontSize = sampleConfig.MicVolumePa.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.MicVolumePa.SymbolFontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.SymbolFontSize: %s, fallback to %s",
+				err,
+				sampleConfig.MicVolumePa.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.SymbolFontSize = sampleConfig.MicVolumePa.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.SymbolFontSize, fallback to %s",
+				sampleConfig.MicVolumePa.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.SymbolFontSize = sampleConfig.MicVolumePa.FontSize
+		}
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Left.Color == "" {
+		sampleConfig.MicVolumePa.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Left.Background == "" {
+		sampleConfig.MicVolumePa.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Left.Symbol == "" {
+		sampleConfig.MicVolumePa.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Left.Font == "" {
+		sampleConfig.MicVolumePa.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Left.FontSize == "" {
+		sampleConfig.MicVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.MicVolumePa.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Right.Color == "" {
+		sampleConfig.MicVolumePa.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Right.Background == "" {
+		sampleConfig.MicVolumePa.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Right.Symbol == "" {
+		sampleConfig.MicVolumePa.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Right.Font == "" {
+		sampleConfig.MicVolumePa.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.MicVolumePa.Separator.Right.FontSize == "" {
+		sampleConfig.MicVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.MicVolumePa.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.MicVolumePa.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.MicVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.StreamVolumePa.Enabled will false if not set in config
+	// sampleConfig.StreamVolumePa.HideIdle will false if not set in config
+	// sampleConfig.StreamVolumePa.ShowPaused will false if not set in config
+	// sampleConfig.StreamVolumePa.IgnoreApps can be empty
+	if sampleConfig.StreamVolumePa.Symbol == "" {
+		sampleConfig.StreamVolumePa.Symbol = `🎵`
+	}
+
+	if sampleConfig.StreamVolumePa.Step == 0 {
+		sampleConfig.StreamVolumePa.Step = 5
+	}
+
+	if sampleConfig.StreamVolumePa.WheelUp == 0 {
+		sampleConfig.StreamVolumePa.WheelUp = 4
+	}
+
+	if sampleConfig.StreamVolumePa.WheelDown == 0 {
+		sampleConfig.StreamVolumePa.WheelDown = 5
+	}
+
+	if sampleConfig.StreamVolumePa.MaxVolumeLimit <= 0 || sampleConfig.StreamVolumePa.MaxVolumeLimit >= 150 {
+		sampleConfig.StreamVolumePa.MaxVolumeLimit = 100
+	}
+
+	if sampleConfig.StreamVolumePa.NextButton == 0 {
+		sampleConfig.StreamVolumePa.NextButton = 1
+	}
+
+	if sampleConfig.StreamVolumePa.MuteButton == 0 {
+		sampleConfig.StreamVolumePa.MuteButton = 2
+	}
+
+	if sampleConfig.StreamVolumePa.MutedColor == "" {
+		sampleConfig.StreamVolumePa.MutedColor = "#666666"
+	}
+
+	if sampleConfig.StreamVolumePa.MaxNameLength <= 0 {
+		sampleConfig.StreamVolumePa.MaxNameLength = 20
+	}
+
+	if sampleConfig.StreamVolumePa.Color == "" {
+		sampleConfig.StreamVolumePa.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.StreamVolumePa.Background == "" {
+		sampleConfig.StreamVolumePa.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.StreamVolumePa.Font == "" {
+		sampleConfig.StreamVolumePa.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.StreamVolumePa.FontSize == "" {
+		sampleConfig.StreamVolumePa.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.StreamVolumePa.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Left.Color == "" {
+		sampleConfig.StreamVolumePa.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Left.Background == "" {
+		sampleConfig.StreamVolumePa.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Left.Symbol == "" {
+		sampleConfig.StreamVolumePa.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Left.Font == "" {
+		sampleConfig.StreamVolumePa.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Left.FontSize == "" {
+		sampleConfig.StreamVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.StreamVolumePa.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Right.Color == "" {
+		sampleConfig.StreamVolumePa.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Right.Background == "" {
+		sampleConfig.StreamVolumePa.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Right.Symbol == "" {
+		sampleConfig.StreamVolumePa.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Right.Font == "" {
+		sampleConfig.StreamVolumePa.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.StreamVolumePa.Separator.Right.FontSize == "" {
+		sampleConfig.StreamVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.StreamVolumePa.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.StreamVolumePa.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.StreamVolumePa.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.CardProfile.Enabled will false if not set in config
+	// sampleConfig.CardProfile.Card can be empty, in that case first card is used
+	// sampleConfig.CardProfile.Profiles can be empty, in that case all available profiles of card are cycled
+	// sampleConfig.CardProfile.Aliases can be empty, in that case profile descriptions are shown as is
+	// sampleConfig.CardProfile.HideMissing will false if not set in config
+	if re, err := regexp.Compile(sampleConfig.CardProfile.Card); err != nil {
+		log.Printf("Unable to compile sampleConfig.CardProfile.Card regexp, disabling card-profile: %s", err)
+
+		sampleConfig.CardProfile.Enabled = false
+	} else {
+		sampleConfig.CardProfile.CardRe = re
+	}
+
+	if sampleConfig.CardProfile.Symbol == "" {
+		sampleConfig.CardProfile.Symbol = `🎧`
+	}
+
+	if sampleConfig.CardProfile.NextButton == 0 {
+		sampleConfig.CardProfile.NextButton = 1
+	}
+
+	if sampleConfig.CardProfile.PrevButton == 0 {
+		sampleConfig.CardProfile.PrevButton = 3
+	}
+
+	if sampleConfig.CardProfile.WheelUp == 0 {
+		sampleConfig.CardProfile.WheelUp = 4
+	}
+
+	if sampleConfig.CardProfile.WheelDown == 0 {
+		sampleConfig.CardProfile.WheelDown = 5
+	}
+
+	if sampleConfig.CardProfile.MissingText == "" {
+		sampleConfig.CardProfile.MissingText = "-"
+	}
+
+	if sampleConfig.CardProfile.UnavailableText == "" {
+		sampleConfig.CardProfile.UnavailableText = "n/a"
+	}
+
+	if sampleConfig.CardProfile.Color == "" {
+		sampleConfig.CardProfile.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.CardProfile.Background == "" {
+		sampleConfig.CardProfile.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.CardProfile.Font == "" {
+		sampleConfig.CardProfile.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.CardProfile.FontSize == "" {
+		sampleConfig.CardProfile.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CardProfile.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CardProfile.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CardProfile.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.CardProfile.Separator.Left.Color == "" {
+		sampleConfig.CardProfile.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.CardProfile.Separator.Left.Background == "" {
+		sampleConfig.CardProfile.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.CardProfile.Separator.Left.Symbol == "" {
+		sampleConfig.CardProfile.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.CardProfile.Separator.Left.Font == "" {
+		sampleConfig.CardProfile.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.CardProfile.Separator.Left.FontSize == "" {
+		sampleConfig.CardProfile.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CardProfile.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CardProfile.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CardProfile.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.CardProfile.Separator.Right.Color == "" {
+		sampleConfig.CardProfile.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.CardProfile.Separator.Right.Background == "" {
+		sampleConfig.CardProfile.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.CardProfile.Separator.Right.Symbol == "" {
+		sampleConfig.CardProfile.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.CardProfile.Separator.Right.Font == "" {
+		sampleConfig.CardProfile.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.CardProfile.Separator.Right.FontSize == "" {
+		sampleConfig.CardProfile.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CardProfile.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CardProfile.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CardProfile.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CardProfile.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.Sensors.Enabled will false if not set in config
+	// sampleConfig.Sensors.Items[].Sensors can be empty, in that case all inputs of given kind of chip are used
+	// sampleConfig.Sensors.Items[].Warning and Critical thresholds are not checked if not set in config
+	if len(sampleConfig.Sensors.Items) == 0 {
+		sampleConfig.Sensors.Enabled = false
+	}
+
+	if sampleConfig.Sensors.HwmonRoot == "" {
+		sampleConfig.Sensors.HwmonRoot = "/sys/class/hwmon"
+	}
+
+	if sampleConfig.Sensors.Interval <= 0 {
+		sampleConfig.Sensors.Interval = 3
+	}
+
+	if sampleConfig.Sensors.Unit != "F" {
+		sampleConfig.Sensors.Unit = "C"
+	}
+
+	if sampleConfig.Sensors.WarningColor == "" {
+		sampleConfig.Sensors.WarningColor = "#ffaa00"
+	}
+
+	if sampleConfig.Sensors.CriticalColor == "" {
+		sampleConfig.Sensors.CriticalColor = "#ff5555"
+	}
+
+	for i := range sampleConfig.Sensors.Items {
+		item := &sampleConfig.Sensors.Items[i]
+
+		// item.Chip can be empty, in that case all chips are searched
+		for _, chip := range item.Chip {
+			if _, err := filepath.Match(chip, ""); err != nil {
+				log.Printf("Unable to use sampleConfig.Sensors.Items[%d].Chip glob, disabling sensors: %s", i, err)
+
+				sampleConfig.Sensors.Enabled = false
+			}
+		}
+
+		switch item.Kind {
+		case "temp", "fan", "in", "curr", "power":
+		default:
+			item.Kind = "temp"
+		}
+
+		if item.Aggregate != "avg" {
+			item.Aggregate = "max"
+		}
+	}
+
+	if sampleConfig.Sensors.Color == "" {
+		sampleConfig.Sensors.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.Sensors.Background == "" {
+		sampleConfig.Sensors.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.Sensors.Font == "" {
+		sampleConfig.Sensors.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.Sensors.FontSize == "" {
+		sampleConfig.Sensors.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Sensors.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.Sensors.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.Sensors.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.Sensors.Separator.Left.Color == "" {
+		sampleConfig.Sensors.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.Sensors.Separator.Left.Background == "" {
+		sampleConfig.Sensors.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.Sensors.Separator.Left.Symbol == "" {
+		sampleConfig.Sensors.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.Sensors.Separator.Left.Font == "" {
+		sampleConfig.Sensors.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.Sensors.Separator.Left.FontSize == "" {
+		sampleConfig.Sensors.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Sensors.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.Sensors.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.Sensors.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.Sensors.Separator.Right.Color == "" {
+		sampleConfig.Sensors.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.Sensors.Separator.Right.Background == "" {
+		sampleConfig.Sensors.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.Sensors.Separator.Right.Symbol == "" {
+		sampleConfig.Sensors.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.Sensors.Separator.Right.Font == "" {
+		sampleConfig.Sensors.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.Sensors.Separator.Right.FontSize == "" {
+		sampleConfig.Sensors.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.Sensors.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.Sensors.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.Sensors.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.Sensors.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.CPUUsage.Enabled will false if not set in config
+	// sampleConfig.CPUUsage.ShowIOWait will false if not set in config
+	// sampleConfig.CPUUsage.PerCore will false if not set in config
+	if sampleConfig.CPUUsage.StatFile == "" {
+		sampleConfig.CPUUsage.StatFile = "/proc/stat"
+	}
+
+	if sampleConfig.CPUUsage.Interval <= 0 {
+		sampleConfig.CPUUsage.Interval = 2
+	}
+
+	if sampleConfig.CPUUsage.Color == "" {
+		sampleConfig.CPUUsage.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.CPUUsage.Background == "" {
+		sampleConfig.CPUUsage.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.CPUUsage.Font == "" {
+		sampleConfig.CPUUsage.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.CPUUsage.FontSize == "" {
+		sampleConfig.CPUUsage.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUUsage.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CPUUsage.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CPUUsage.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.CPUUsage.Separator.Left.Color == "" {
+		sampleConfig.CPUUsage.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.CPUUsage.Separator.Left.Background == "" {
+		sampleConfig.CPUUsage.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.CPUUsage.Separator.Left.Symbol == "" {
+		sampleConfig.CPUUsage.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.CPUUsage.Separator.Left.Font == "" {
+		sampleConfig.CPUUsage.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.CPUUsage.Separator.Left.FontSize == "" {
+		sampleConfig.CPUUsage.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUUsage.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CPUUsage.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CPUUsage.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.CPUUsage.Separator.Right.Color == "" {
+		sampleConfig.CPUUsage.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.CPUUsage.Separator.Right.Background == "" {
+		sampleConfig.CPUUsage.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.CPUUsage.Separator.Right.Symbol == "" {
+		sampleConfig.CPUUsage.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.CPUUsage.Separator.Right.Font == "" {
+		sampleConfig.CPUUsage.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.CPUUsage.Separator.Right.FontSize == "" {
+		sampleConfig.CPUUsage.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUUsage.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CPUUsage.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUUsage.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CPUUsage.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.CPUFreq.Enabled will false if not set in config
+	// sampleConfig.CPUFreq.Profiles can be empty, in that case clicks do nothing
+	// sampleConfig.CPUFreq.HelperCmd can be empty, in that case only direct writes to sysfs are tried
+	if sampleConfig.CPUFreq.SysfsRoot == "" {
+		sampleConfig.CPUFreq.SysfsRoot = "/sys/devices/system/cpu/cpufreq"
+	}
+
+	if sampleConfig.CPUFreq.Interval <= 0 {
+		sampleConfig.CPUFreq.Interval = 3
+	}
+
+	switch sampleConfig.CPUFreq.Show {
+	case "governor", "epp", "none":
+	default:
+		sampleConfig.CPUFreq.Show = "governor"
+	}
+
+	if sampleConfig.CPUFreq.Color == "" {
+		sampleConfig.CPUFreq.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.CPUFreq.Background == "" {
+		sampleConfig.CPUFreq.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.CPUFreq.Font == "" {
+		sampleConfig.CPUFreq.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.CPUFreq.FontSize == "" {
+		sampleConfig.CPUFreq.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUFreq.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CPUFreq.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.CPUFreq.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.CPUFreq.Separator.Left.Color == "" {
+		sampleConfig.CPUFreq.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.CPUFreq.Separator.Left.Background == "" {
+		sampleConfig.CPUFreq.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.CPUFreq.Separator.Left.Symbol == "" {
+		sampleConfig.CPUFreq.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.CPUFreq.Separator.Left.Font == "" {
+		sampleConfig.CPUFreq.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.CPUFreq.Separator.Left.FontSize == "" {
+		sampleConfig.CPUFreq.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUFreq.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CPUFreq.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.CPUFreq.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.CPUFreq.Separator.Right.Color == "" {
+		sampleConfig.CPUFreq.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.CPUFreq.Separator.Right.Background == "" {
+		sampleConfig.CPUFreq.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.CPUFreq.Separator.Right.Symbol == "" {
+		sampleConfig.CPUFreq.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.CPUFreq.Separator.Right.Font == "" {
+		sampleConfig.CPUFreq.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.CPUFreq.Separator.Right.FontSize == "" {
+		sampleConfig.CPUFreq.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.CPUFreq.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CPUFreq.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.CPUFreq.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.CPUFreq.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.PSI.Enabled will false if not set in config
+	// sampleConfig.PSI.ShowFull will false if not set in config
+	// sampleConfig.PSI.Hook.Cmd can be empty, in that case hook is not run
+	if sampleConfig.PSI.ProcDir == "" {
+		sampleConfig.PSI.ProcDir = "/proc/pressure"
+	}
+
+	if len(sampleConfig.PSI.Resources) == 0 {
+		sampleConfig.PSI.Resources = []string{"cpu", "memory", "io"}
+	}
+
+	for _, resource := range sampleConfig.PSI.Resources {
+		if _, ok := psiLabels[resource]; !ok {
+			log.Printf("Unknown sampleConfig.PSI.Resources item %s, disabling psi", resource)
+
+			sampleConfig.PSI.Enabled = false
+		}
+	}
+
+	if sampleConfig.PSI.Interval <= 0 {
+		sampleConfig.PSI.Interval = 3
+	}
+
+	if sampleConfig.PSI.Warning <= 0 {
+		sampleConfig.PSI.Warning = 10
+	}
+
+	if sampleConfig.PSI.Critical <= 0 {
+		sampleConfig.PSI.Critical = 40
+	}
+
+	if sampleConfig.PSI.WarningColor == "" {
+		sampleConfig.PSI.WarningColor = "#ffaa00"
+	}
+
+	if sampleConfig.PSI.CriticalColor == "" {
+		sampleConfig.PSI.CriticalColor = "#ff5555"
+	}
+
+	if sampleConfig.PSI.Hook.Resource == "" {
+		sampleConfig.PSI.Hook.Resource = "memory"
+	}
+
+	if len(sampleConfig.PSI.Hook.Cmd) > 0 && !slices.Contains(sampleConfig.PSI.Resources, sampleConfig.PSI.Hook.Resource) {
+		log.Printf("sampleConfig.PSI.Hook.Resource %s is not in resources, hook disabled", sampleConfig.PSI.Hook.Resource)
+
+		sampleConfig.PSI.Hook.Cmd = nil
+	}
+
+	if sampleConfig.PSI.Hook.Kind != "some" {
+		sampleConfig.PSI.Hook.Kind = "full"
+	}
+
+	if sampleConfig.PSI.Hook.Threshold <= 0 {
+		sampleConfig.PSI.Hook.Threshold = 20
+	}
+
+	if sampleConfig.PSI.Hook.Duration <= 0 {
+		sampleConfig.PSI.Hook.Duration = 30
+	}
+
+	if sampleConfig.PSI.Color == "" {
+		sampleConfig.PSI.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.PSI.Background == "" {
+		sampleConfig.PSI.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.PSI.Font == "" {
+		sampleConfig.PSI.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.PSI.FontSize == "" {
+		sampleConfig.PSI.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.PSI.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.PSI.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.PSI.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.PSI.Separator.Left.Color == "" {
+		sampleConfig.PSI.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.PSI.Separator.Left.Background == "" {
+		sampleConfig.PSI.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.PSI.Separator.Left.Symbol == "" {
+		sampleConfig.PSI.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.PSI.Separator.Left.Font == "" {
+		sampleConfig.PSI.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.PSI.Separator.Left.FontSize == "" {
+		sampleConfig.PSI.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.PSI.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.PSI.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.PSI.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.PSI.Separator.Right.Color == "" {
+		sampleConfig.PSI.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.PSI.Separator.Right.Background == "" {
+		sampleConfig.PSI.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.PSI.Separator.Right.Symbol == "" {
+		sampleConfig.PSI.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.PSI.Separator.Right.Font == "" {
+		sampleConfig.PSI.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.PSI.Separator.Right.FontSize == "" {
+		sampleConfig.PSI.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.PSI.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.PSI.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.PSI.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.PSI.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	// sampleConfig.AppButtons.Enabled will false if not set in config
+	if sampleConfig.AppButtons.Color == "" {
+		sampleConfig.AppButtons.Color = sampleConfig.Color
+	}
+
+	if sampleConfig.AppButtons.Background == "" {
+		sampleConfig.AppButtons.Background = sampleConfig.Background
+	}
+
+	if sampleConfig.AppButtons.Font == "" {
+		sampleConfig.AppButtons.Font = sampleConfig.Font
+	}
+
+	if sampleConfig.AppButtons.FontSize == "" {
+		sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.AppButtons.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.AppButtons.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.AppButtons.FontSize, fallback to %s",
+				sampleConfig.FontSize,
+			)
+
+			sampleConfig.AppButtons.FontSize = sampleConfig.FontSize
+		}
+	}
+
+	if sampleConfig.AppButtons.Separator.Left.Color == "" {
+		sampleConfig.AppButtons.Separator.Left.Color = sampleConfig.Separator.Left.Color
+	}
+
+	if sampleConfig.AppButtons.Separator.Left.Background == "" {
+		sampleConfig.AppButtons.Separator.Left.Background = sampleConfig.Separator.Left.Background
+	}
+
+	if sampleConfig.AppButtons.Separator.Left.Symbol == "" {
+		sampleConfig.AppButtons.Separator.Left.Symbol = sampleConfig.Separator.Left.Symbol
+	}
+
+	if sampleConfig.AppButtons.Separator.Left.Font == "" {
+		sampleConfig.AppButtons.Separator.Left.Font = sampleConfig.Separator.Left.Font
+	}
+
+	if sampleConfig.AppButtons.Separator.Left.FontSize == "" {
+		sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.AppButtons.Separator.Left.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.AppButtons.Separator.Left.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+
+		if !matched {
+			log.Printf(
+				"Unable to set sampleConfig.AppButtons.Separator.Left.FontSize, fallback to %s",
+				sampleConfig.Separator.Left.FontSize,
+			)
+
+			sampleConfig.AppButtons.Separator.Left.FontSize = sampleConfig.Separator.Left.FontSize
+		}
+	}
+
+	if sampleConfig.AppButtons.Separator.Right.Color == "" {
+		sampleConfig.AppButtons.Separator.Right.Color = sampleConfig.Separator.Right.Color
+	}
+
+	if sampleConfig.AppButtons.Separator.Right.Background == "" {
+		sampleConfig.AppButtons.Separator.Right.Background = sampleConfig.Separator.Right.Background
+	}
+
+	if sampleConfig.AppButtons.Separator.Right.Symbol == "" {
+		sampleConfig.AppButtons.Separator.Right.Symbol = sampleConfig.Separator.Right.Symbol
+	}
+
+	if sampleConfig.AppButtons.Separator.Right.Font == "" {
+		sampleConfig.AppButtons.Separator.Right.Font = sampleConfig.Separator.Right.Font
+	}
+
+	if sampleConfig.AppButtons.Separator.Right.FontSize == "" {
+		sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+	} else {
+		matched, err := regexp.MatchString(
+			`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+			sampleConfig.AppButtons.Separator.Right.FontSize,
+		)
+
+		if err != nil {
+			log.Printf(
+				"Unable to set sampleConfig.AppButtons.Separator.Right.FontSize: %s, fallback to %s",
+				err,
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+
+		if !matched {
+			log.Printf("Unable to set sampleConfig.AppButtons.Separator.Right.FontSize, fallback to %s",
+				sampleConfig.Separator.Right.FontSize,
+			)
+
+			sampleConfig.AppButtons.Separator.Right.FontSize = sampleConfig.Separator.Right.FontSize
+		}
+	}
+
+	if len(sampleConfig.Apps) == 0 {
+		sampleConfig.AppButtons.Enabled = false
+	} else {
+		for num, app := range sampleConfig.Apps {
+			// app.Instance can be missing.
+			// app.Class can be missing.
+			if app.Name == "" {
+				sampleConfig.Apps[num].Name = fmt.Sprintf("app%d", num)
+			}
+
+			// app.Args can be empty slice. In that case command will be run without aruments.
+			if app.Cmd == "" {
+				// If command omitted it will be just /usr/bin/true.
+				sampleConfig.Apps[num].Cmd = "true"
+			}
+
+			if app.Color == "" {
+				sampleConfig.Apps[num].Color = sampleConfig.AppButtons.Color
+			}
+
+			if app.Background == "" {
+				sampleConfig.Apps[num].Background = sampleConfig.AppButtons.Background
+			}
+
+			if app.Font == "" {
+				sampleConfig.Apps[num].Font = sampleConfig.AppButtons.Font
+			}
+
+			if app.FontSize == "" {
+				sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
+			} else {
+				matched, err := regexp.MatchString(
+					`^(xx-small|x-small|small|medium|large|x-large|xx-large|smaller|larger)$`,
+					app.FontSize,
+				)
+
+				if err != nil {
+					log.Printf(
+						"Unable to set %s.FontSize: %s, fallback to %s",
+						app.Name,
+						err,
+						sampleConfig.AppButtons.FontSize,
+					)
+
+					sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
+				}
+
+				if !matched {
+					log.Printf("Unable to set %s.FontSize, fallback to %s",
+						app.Name,
+						sampleConfig.AppButtons.FontSize,
+					)
+
+					sampleConfig.Apps[num].FontSize = sampleConfig.AppButtons.FontSize
+				}
+			}
+
+			if app.Border == "" {
+				sampleConfig.Apps[num].Border = sampleConfig.AppButtons.Color
+			}
+
+			if app.BorderActive == "" {
 				sampleConfig.Apps[num].BorderActive = sampleConfig.AppButtons.Color
 			}
 
@@ -2028,6 +4389,18 @@ func ReadConf(defaultConfig []byte) (*MyConfig, error) {
 			if app.FullText == "" {
 				sampleConfig.Apps[num].FullText = fmt.Sprintf(" %d ", num)
 			}
+
+			// app.StartupID can be omitted, in that case it is false
+			// Only i3 can put new window to given workspace, so app.Workspace implies app.I3Exec.
+			if app.Workspace != "" && !app.I3Exec {
+				log.Printf(
+					"%s.workspace is set, so enabling %s.i3_exec",
+					sampleConfig.Apps[num].Name,
+					sampleConfig.Apps[num].Name,
+				)
+
+				sampleConfig.Apps[num].I3Exec = true
+			}
 		}
 	}
 
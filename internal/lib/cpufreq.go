@@ -0,0 +1,163 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+const (
+	cpuGovernorFile = "scaling_governor"
+	cpuEPPFile      = "energy_performance_preference"
+)
+
+// cpuFreqRefresh asks UpdateCPUFreq to update block right now instead of waiting for next tick.
+var cpuFreqRefresh = make(chan struct{}, 1)
+
+// UpdateCPUFreq reads average and maximum frequency of cpufreq policies and active governor or energy performance
+// preference.
+func (c *MyConfig) UpdateCPUFreq() {
+	var (
+		InitialDelay       = 100 * time.Millisecond
+		LoopIterationDelay = time.Duration(c.CPUFreq.Interval) * time.Second
+		Delay              = InitialDelay
+		ticker             = time.NewTicker(Delay)
+	)
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-cpuFreqRefresh:
+		}
+
+		if Delay == InitialDelay {
+			Delay = LoopIterationDelay
+			ticker.Reset(Delay)
+		}
+
+		str, err := c.CPUFreqString()
+
+		if err != nil {
+			log.Printf("Unable to get cpu frequency: %s", err)
+
+			continue
+		}
+
+		if c.Values.CPUFreq != str {
+			c.Values.CPUFreq = str
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// CPUFreqPolicies returns sysfs dirs of cpufreq policies.
+func (c *MyConfig) CPUFreqPolicies() ([]string, error) {
+	policies, err := filepath.Glob(filepath.Join(c.CPUFreq.SysfsRoot, "policy*"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policies) == 0 {
+		return nil, errors.New("no cpufreq policies found in " + c.CPUFreq.SysfsRoot) //nolint: err113
+	}
+
+	return policies, nil
+}
+
+// CPUFreqString returns average and maximum current frequency in GHz followed by governor or epp of first policy.
+func (c *MyConfig) CPUFreqString() (string, error) {
+	var (
+		sum   float64
+		maxF  float64
+		count int
+	)
+
+	policies, err := c.CPUFreqPolicies()
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, policy := range policies {
+		// Policy of offline cpu has no current frequency.
+		freq, err := ReadSysfsFloat(policy, "scaling_cur_freq")
+
+		if err != nil {
+			continue
+		}
+
+		sum += freq
+		maxF = max(maxF, freq)
+		count++
+	}
+
+	if count == 0 {
+		return "", errors.New("no cpufreq policy reports current frequency") //nolint: err113
+	}
+
+	// Frequencies are in kHz.
+	str := fmt.Sprintf("%.1f/%.1fGHz", sum/float64(count)/1e6, maxF/1e6)
+
+	if c.CPUFreq.Show == "none" {
+		return str, nil
+	}
+
+	// Not every driver supports epp, show governor then.
+	if c.CPUFreq.Show == "epp" {
+		if epp, err := ReadSysfsString(policies[0], cpuEPPFile); err == nil {
+			return str + " " + epp, nil
+		}
+	}
+
+	if governor, err := ReadSysfsString(policies[0], cpuGovernorFile); err == nil {
+		str += " " + governor
+	}
+
+	return str, nil
+}
+
+// CPUFreqHandler applies governor and epp of profile bound to clicked button to all policies.
+func (c *MyConfig) CPUFreqHandler(e ClickEvent) {
+	for _, profile := range c.CPUFreq.Profiles {
+		if profile.Button != e.Button {
+			continue
+		}
+
+		policies, err := c.CPUFreqPolicies()
+
+		if err != nil {
+			log.Printf("Unable to switch cpu frequency profile: %s", err)
+
+			return
+		}
+
+		var writes []SysfsWrite
+
+		// Epp can not be changed while performance governor is active, so governor goes first.
+		for _, policy := range policies {
+			if profile.Governor != "" {
+				writes = append(writes, SysfsWrite{File: filepath.Join(policy, cpuGovernorFile), Value: profile.Governor})
+			}
+		}
+
+		for _, policy := range policies {
+			if profile.EPP != "" {
+				writes = append(writes, SysfsWrite{File: filepath.Join(policy, cpuEPPFile), Value: profile.EPP})
+			}
+		}
+
+		if err := WriteSysfsAll(writes, c.CPUFreq.HelperCmd); err != nil {
+			log.Printf("Unable to set cpu governor %q and epp %q: %s", profile.Governor, profile.EPP, err)
+		}
+
+		select {
+		case cpuFreqRefresh <- struct{}{}:
+		default:
+		}
+
+		return
+	}
+}
@@ -1,28 +1,30 @@
 package lib
 
 import (
-	"bufio"
+	"fmt"
 	"log"
-	"os"
-	"strconv"
+	"slices"
+	"strings"
 	"time"
 )
 
-// UpdateCPUTemperature gets and updates average CPU cores temperature.
+// UpdateCPUTemperature gets and updates CPU temperature: average or maximum of selected sensors, or all of them.
 func (c *MyConfig) UpdateCPUTemperature() {
 	var (
-		n                  = len(c.CPUTemp.File)
 		InitialDelay       = 100 * time.Millisecond
 		LoopIterationDelay = 3 * time.Second
 		Delay              = InitialDelay
 		ticker             = time.NewTicker(Delay)
+		sensors            []HwmonSensor
+		lastScan           time.Time
 	)
 
 	for range ticker.C {
 		var (
-			temperature = make([]int64, n)
+			temperature []int64
+			failed      bool
 			tSum        int64
-			tAvg        int64
+			tAgg        int64
 		)
 
 		if Delay == InitialDelay {
@@ -30,57 +32,106 @@ func (c *MyConfig) UpdateCPUTemperature() {
 			ticker.Reset(Delay)
 		}
 
-		for i, filename := range c.CPUTemp.File {
-			file, err := os.Open(filename)
+		// Hwmon chips can appear late on boot or be renumbered on driver reload, so search again if something fails.
+		if len(sensors) == 0 && time.Since(lastScan) >= time.Minute {
+			sensors = c.CPUTempSensors()
+			lastScan = time.Now()
+		}
+
+		for _, s := range sensors {
+			temp, err := ReadHwmonValue(s.File)
 
+			// Failed sensor must not drag average down, so it is skipped.
 			if err != nil {
-				log.Printf("Unable to open %s: %s", filename, err)
-			} else {
-				reader := bufio.NewReader(file)
-				line, _, err := reader.ReadLine()
-
-				if err != nil {
-					log.Printf("Unable to read from %s: %s", filename, err)
-					err = file.Close()
-
-					if err != nil {
-						log.Printf("Unable to close %s: %s", filename, err)
-					}
-				} else {
-					err = file.Close()
-
-					if err != nil {
-						log.Printf("Unable to close %s: %s", filename, err)
-					} else {
-						temp, err := strconv.ParseInt(string(line), 10, 32)
-
-						if err != nil {
-							log.Printf("Unable to convert string to number from file %s: %s", filename, err)
-						} else {
-							if temp > 1000 {
-								temp /= 1000
-							}
-
-							temperature[i] = temp
-						}
-					}
-				}
+				log.Printf("Unable to read temperature from %s: %s", s.File, err)
+
+				failed = true
+
+				continue
 			}
+
+			// Hwmon reports millidegrees, but explicitly configured file can contain just degrees.
+			if temp > 1000 {
+				temp /= 1000
+			}
+
+			temperature = append(temperature, temp)
+		}
+
+		if failed && len(c.CPUTemp.Chip) > 0 {
+			sensors = nil
 		}
 
-		if len(temperature) == 1 {
-			tAvg = temperature[0]
-		} else {
+		// Nothing could be read, so last temperature would be frozen on bar. Show it as unknown instead.
+		if len(temperature) == 0 {
+			if c.Values.CPUTemperatures != nil {
+				c.Values.CPUTemperatures = nil
+				c.Channels.UpdateReady <- true
+			}
+
+			continue
+		}
+
+		switch c.CPUTemp.Aggregate {
+		case "max":
+			tAgg = slices.Max(temperature)
+		default:
 			for _, t := range temperature {
 				tSum += t
 			}
 
-			tAvg = tSum / int64(len(temperature))
+			tAgg = tSum / int64(len(temperature))
 		}
 
-		if c.Values.CPUTemperature != tAvg {
-			c.Values.CPUTemperature = tAvg
+		if c.Values.CPUTemperature != tAgg || !slices.Equal(c.Values.CPUTemperatures, temperature) {
+			c.Values.CPUTemperature = tAgg
+			c.Values.CPUTemperatures = temperature
 			c.Channels.UpdateReady <- true
 		}
 	}
 }
+
+// CPUTempSensors returns sensors selected by chip name and label globs or explicitly configured files.
+func (c *MyConfig) CPUTempSensors() []HwmonSensor {
+	var sensors []HwmonSensor
+
+	if len(c.CPUTemp.Chip) == 0 {
+		for _, file := range c.CPUTemp.File {
+			sensors = append(sensors, HwmonSensor{File: file})
+		}
+
+		return sensors
+	}
+
+	sensors, err := FindHwmonSensors(c.CPUTemp.HwmonRoot, "temp", c.CPUTemp.Chip, c.CPUTemp.Labels)
+
+	if err != nil {
+		log.Printf("Unable to search hwmon sensors in %s: %s", c.CPUTemp.HwmonRoot, err)
+	}
+
+	if len(sensors) == 0 {
+		log.Printf("No temperature sensors of chips %v with labels %v found", c.CPUTemp.Chip, c.CPUTemp.Labels)
+	}
+
+	return sensors
+}
+
+// FormatTemperatures returns aggregated temperature or, in "all" mode, temperatures of all sensors. Temperature is
+// unknown if there are no readings.
+func FormatTemperatures(aggregate string, temperature int64, temperatures []int64) string {
+	if len(temperatures) == 0 {
+		return "?"
+	}
+
+	if aggregate != "all" {
+		return fmt.Sprintf("%d°", temperature)
+	}
+
+	s := make([]string, len(temperatures))
+
+	for i, t := range temperatures {
+		s[i] = fmt.Sprintf("%d°", t)
+	}
+
+	return strings.Join(s, " ")
+}
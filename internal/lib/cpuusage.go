@@ -0,0 +1,174 @@
+package lib
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CPUTimes is cumulative time of one line of /proc/stat in jiffies.
+type CPUTimes struct {
+	Total  uint64
+	Idle   uint64
+	IOWait uint64
+}
+
+// CPUUsage is utilisation of cpu between two readings of /proc/stat, in percents.
+type CPUUsage struct {
+	Busy   float64
+	IOWait float64
+}
+
+// UpdateCPUUsage calculates total and per-core cpu utilisation from /proc/stat.
+func (c *MyConfig) UpdateCPUUsage() {
+	var (
+		InitialDelay       = 100 * time.Millisecond
+		LoopIterationDelay = time.Duration(c.CPUUsage.Interval) * time.Second
+		Delay              = InitialDelay
+		ticker             = time.NewTicker(Delay)
+		prev               []CPUTimes
+	)
+
+	for range ticker.C {
+		if Delay == InitialDelay {
+			Delay = LoopIterationDelay
+			ticker.Reset(Delay)
+		}
+
+		cur, err := ReadCPUTimes(c.CPUUsage.StatFile)
+
+		if err != nil {
+			log.Printf("Unable to read cpu statistics from %s: %s", c.CPUUsage.StatFile, err)
+
+			continue
+		}
+
+		// First reading or cpu hotplug, nothing to compare with.
+		if len(prev) != len(cur) {
+			prev = cur
+
+			continue
+		}
+
+		usage := make([]CPUUsage, len(cur))
+
+		for i := range cur {
+			usage[i] = CPUTimesDiff(prev[i], cur[i])
+		}
+
+		prev = cur
+
+		summary := fmt.Sprintf("%.0f%%", usage[0].Busy)
+
+		if c.CPUUsage.ShowIOWait {
+			summary += fmt.Sprintf(" io %.0f%%", usage[0].IOWait)
+		}
+
+		cores := CPUUsageBars(usage[1:])
+
+		if c.Values.CPUUsage != summary || c.Values.CPUUsageCores != cores {
+			c.Values.CPUUsage = summary
+			c.Values.CPUUsageCores = cores
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// ReadCPUTimes parses /proc/stat, first element is summary "cpu" line, others are "cpuN" lines in file order.
+func ReadCPUTimes(file string) ([]CPUTimes, error) {
+	var times []CPUTimes
+
+	f, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		// Fields are: user nice system idle iowait irq softirq steal guest guest_nice. Guest time is already
+		// counted in user and nice.
+		if len(fields) < 5 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		var t CPUTimes
+
+		for i, field := range fields[1:min(len(fields), 9)] {
+			v, err := strconv.ParseUint(field, 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("malformed line %q: %w", scanner.Text(), err)
+			}
+
+			t.Total += v
+
+			switch i {
+			case 3:
+				t.Idle = v
+			case 4:
+				t.IOWait = v
+			}
+		}
+
+		times = append(times, t)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(times) == 0 {
+		return nil, errors.New("no cpu lines found") //nolint: err113
+	}
+
+	return times, nil
+}
+
+// CPUTimesDiff returns utilisation between two readings. Iowait is idle time too, so it is not counted as busy.
+func CPUTimesDiff(prev, cur CPUTimes) CPUUsage {
+	var idle, iowait float64
+
+	// Counters can go backwards on cpu hotplug.
+	if cur.Total <= prev.Total {
+		return CPUUsage{}
+	}
+
+	// Iowait is not reliable and can decrease, see proc(5), so only its growth is counted.
+	if cur.IOWait > prev.IOWait {
+		iowait = float64(cur.IOWait - prev.IOWait)
+	}
+
+	if cur.Idle > prev.Idle {
+		idle = float64(cur.Idle - prev.Idle)
+	}
+
+	total := float64(cur.Total - prev.Total)
+
+	return CPUUsage{
+		Busy:   max(0, (total-idle-iowait)/total*100),
+		IOWait: min(iowait/total*100, 100),
+	}
+}
+
+// CPUUsageBars returns busy percent of each core as bar symbol.
+func CPUUsageBars(usage []CPUUsage) string {
+	bars := make([]rune, len(usage))
+
+	for i, u := range usage {
+		level := int(u.Busy) * len(sparks) / 101
+		bars[i] = sparks[max(0, min(level, len(sparks)-1))]
+	}
+
+	return string(bars)
+}
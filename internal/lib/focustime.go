@@ -0,0 +1,341 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	"go.i3wm.org/i3"
+)
+
+// FocusDay contains seconds of focused time per window class and per workspace for one day.
+type FocusDay struct {
+	Class     map[string]int64 `json:"class"`
+	Workspace map[string]int64 `json:"workspace"`
+}
+
+// FocusTracker accumulates time spent in focused windows. Days are keyed by date in 2006-01-02 format.
+type FocusTracker struct {
+	mu        sync.Mutex
+	file      string
+	days      map[string]*FocusDay
+	class     string
+	workspace string
+}
+
+// FT tracks focused time of window classes and workspaces.
+var FT FocusTracker
+
+// UpdateFocusTime accumulates focused time every second, pauses when user is idle or screen is locked and
+// periodically saves daily totals to file under XDG state dir.
+func (c *MyConfig) UpdateFocusTime() {
+	var (
+		InitialDelay       = 100 * time.Millisecond
+		LoopIterationDelay = 1 * time.Second
+		Delay              = InitialDelay
+		ticker             = time.NewTicker(Delay)
+		last               = time.Now()
+		lastCheck          time.Time
+		lastSave           = time.Now()
+		idle               bool
+	)
+
+	file, err := xdg.StateFile("i3status-go/focus-time.json")
+
+	if err != nil {
+		log.Printf("Unable to locate focus time file, tracked time will not survive restart: %s", err)
+	}
+
+	if err := FT.Load(file); err != nil {
+		log.Printf("Unable to load focus time from %s: %s", file, err)
+	}
+
+	for now := range ticker.C {
+		if Delay == InitialDelay {
+			Delay = LoopIterationDelay
+			ticker.Reset(Delay)
+		}
+
+		elapsed := now.Sub(last)
+		last = now
+
+		if now.Sub(lastCheck) >= time.Duration(c.FocusTime.CheckInterval)*time.Second {
+			idle = c.FocusIdle()
+			lastCheck = now
+		}
+
+		// Much longer gap between ticks means that machine was suspended, do not count it.
+		if !idle && elapsed < 3*LoopIterationDelay {
+			FT.Add(now, elapsed)
+		}
+
+		if now.Sub(lastSave) >= time.Duration(c.FocusTime.SaveInterval)*time.Second {
+			if err := FT.Save(c.FocusTime.KeepDays); err != nil {
+				log.Printf("Unable to save focus time: %s", err)
+			}
+
+			lastSave = now
+		}
+
+		class, spent := FT.Current(now)
+
+		status := c.FocusTime.Symbol
+
+		if class != "" {
+			status += fmt.Sprintf(" %s %s", html.EscapeString(class), FormatSeconds(spent))
+		}
+
+		if idle {
+			status += " ⏸"
+		}
+
+		if c.Values.FocusTime != status {
+			c.Values.FocusTime = status
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// FocusIdle returns true if idle flag file exists or idle command exits successfully.
+func (c *MyConfig) FocusIdle() bool {
+	if c.FocusTime.IdleFile != "" {
+		if _, err := os.Stat(c.FocusTime.IdleFile); err == nil {
+			return true
+		}
+	}
+
+	if len(c.FocusTime.IdleCmd) == 0 {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.FocusTime.IdleCmd[0], c.FocusTime.IdleCmd[1:]...) //nolint: gosec
+	cmd.Dir = "/"
+
+	// Non-zero exit status is ordinary answer "not idle", so do not log it.
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	if err != nil && !errors.As(err, &exitErr) {
+		log.Printf("Unable to run '%s': %s", strings.Join(c.FocusTime.IdleCmd, " "), err)
+	}
+
+	return err == nil
+}
+
+// FocusTimeReport sends today's summary to configured report command.
+func (c *MyConfig) FocusTimeReport() {
+	cmd := append([]string{}, c.FocusTime.ReportCmd...)
+	cmd = append(cmd, FT.Report(time.Now(), c.FocusTime.ReportTop))
+
+	c.Channels.RunChan <- cmd
+}
+
+// Switch remembers class and workspace of newly focused window, if nothing is focused n is nil. Workspace is taken
+// from i3 events, not queried here, so that delayed call does not charge time to workspace focused later.
+func (ft *FocusTracker) Switch(n *i3.Node, workspace string) {
+	var class string
+
+	if n != nil {
+		class = n.WindowProperties.Class
+	} else {
+		workspace = ""
+	}
+
+	ft.mu.Lock()
+	ft.class = class
+	ft.workspace = workspace
+	ft.mu.Unlock()
+}
+
+// Add adds elapsed time to currently focused class and workspace.
+func (ft *FocusTracker) Add(now time.Time, elapsed time.Duration) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.class == "" {
+		return
+	}
+
+	day := ft.day(now)
+	day.Class[ft.class] += int64(elapsed.Seconds() + 0.5)
+
+	if ft.workspace != "" {
+		day.Workspace[ft.workspace] += int64(elapsed.Seconds() + 0.5)
+	}
+}
+
+// Current returns currently focused class and time spent in it today, in seconds.
+func (ft *FocusTracker) Current(now time.Time) (string, int64) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.class == "" {
+		return "", 0
+	}
+
+	return ft.class, ft.day(now).Class[ft.class]
+}
+
+// Report returns human-readable summary of given day, limited to top entries.
+func (ft *FocusTracker) Report(now time.Time, top int) string {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	var (
+		day   = ft.day(now)
+		total int64
+	)
+
+	for _, spent := range day.Class {
+		total += spent
+	}
+
+	report := fmt.Sprintf("Total: %s\n\nApplications:\n", FormatSeconds(total))
+	report += topEntries(day.Class, top)
+	report += "\nWorkspaces:\n"
+	report += topEntries(day.Workspace, top)
+
+	return strings.TrimRight(report, "\n")
+}
+
+// Load reads saved daily totals from given file. Missing file is not an error. File that can not be decoded is moved
+// aside with .bad suffix, so it is not overwritten by next Save, and totals start from scratch.
+func (ft *FocusTracker) Load(file string) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	ft.file = file
+	ft.days = map[string]*FocusDay{}
+
+	if file == "" {
+		return nil
+	}
+
+	buf, err := os.ReadFile(file)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := json.Unmarshal(buf, &ft.days); err != nil {
+		ft.days = map[string]*FocusDay{}
+
+		// Totals are not saved then, otherwise they would overwrite the file.
+		if err := os.Rename(file, file+".bad"); err != nil {
+			ft.file = ""
+
+			return fmt.Errorf("unable to decode focus time, saving is disabled: %w", err)
+		}
+
+		return fmt.Errorf("unable to decode focus time, moved to %s.bad: %w", file, err)
+	}
+
+	return nil
+}
+
+// Save writes daily totals to file, days older than keepDays are dropped.
+func (ft *FocusTracker) Save(keepDays int) error {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.file == "" {
+		return nil
+	}
+
+	oldest := time.Now().AddDate(0, 0, -keepDays).Format(time.DateOnly)
+
+	for date := range ft.days {
+		if date < oldest {
+			delete(ft.days, date)
+		}
+	}
+
+	buf, err := json.Marshal(ft.days)
+
+	if err != nil {
+		return err
+	}
+
+	// Write to temporary file and rename it, so we never leave half-written file.
+	tmp := filepath.Join(filepath.Dir(ft.file), "."+filepath.Base(ft.file))
+
+	if err := os.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, ft.file)
+}
+
+// day returns totals for day of given time, creating them if needed. Caller must hold ft.mu.
+func (ft *FocusTracker) day(now time.Time) *FocusDay {
+	if ft.days == nil {
+		ft.days = map[string]*FocusDay{}
+	}
+
+	date := now.Format(time.DateOnly)
+
+	day, exist := ft.days[date]
+
+	if !exist || day == nil {
+		day = &FocusDay{}
+		ft.days[date] = day
+	}
+
+	if day.Class == nil {
+		day.Class = map[string]int64{}
+	}
+
+	if day.Workspace == nil {
+		day.Workspace = map[string]int64{}
+	}
+
+	return day
+}
+
+// topEntries formats given totals as lines sorted by time spent, limited to top entries.
+func topEntries(totals map[string]int64, top int) string {
+	var (
+		names []string
+		lines string
+	)
+
+	for name := range totals {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return totals[names[i]] > totals[names[j]] })
+
+	for i, name := range names {
+		if i >= top {
+			break
+		}
+
+		lines += fmt.Sprintf("%s %s\n", FormatSeconds(totals[name]), name)
+	}
+
+	return lines
+}
+
+// FormatSeconds formats given amount of seconds as h:mm.
+func FormatSeconds(seconds int64) string {
+	return fmt.Sprintf("%d:%02d", seconds/3600, seconds%3600/60)
+}
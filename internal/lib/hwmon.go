@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HwmonSensor is one input file of hwmon chip, e.g. /sys/class/hwmon/hwmon4/temp2_input with label "Core 0".
+type HwmonSensor struct {
+	Chip  string
+	Label string
+	File  string
+}
+
+// FindHwmonSensors returns inputs of given kind ("temp", "fan", "in", ...) of chips matching one of chip globs, with
+// labels matching one of label globs. Empty chip or label list matches all chips or inputs. Input without label file is labelled by its name,
+// e.g. "temp1". Hwmon numbering is not stable between boots, so sensors are searched by names, not by paths.
+func FindHwmonSensors(root, kind string, chips, labels []string) ([]HwmonSensor, error) {
+	var sensors []HwmonSensor
+
+	dirs, err := os.ReadDir(root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range dirs {
+		dir := filepath.Join(root, d.Name())
+
+		name, err := ReadSysfsString(dir, "name")
+
+		if err != nil {
+			continue
+		}
+
+		if !MatchAny(chips, name) {
+			continue
+		}
+
+		suffix := "_input"
+
+		inputs, err := filepath.Glob(filepath.Join(dir, kind+"*"+suffix))
+
+		if err != nil {
+			continue
+		}
+
+		// Many drivers, e.g. amdgpu, report only average power.
+		if len(inputs) == 0 && kind == "power" {
+			suffix = "_average"
+			inputs, _ = filepath.Glob(filepath.Join(dir, kind+"*"+suffix))
+		}
+
+		var chipSensors []HwmonSensor
+
+		for _, input := range inputs {
+			base := strings.TrimSuffix(filepath.Base(input), suffix)
+
+			label, err := ReadSysfsString(dir, base+"_label")
+
+			if err != nil {
+				label = base
+			}
+
+			if !MatchAny(labels, label) {
+				continue
+			}
+
+			chipSensors = append(chipSensors, HwmonSensor{Chip: name, Label: label, File: input})
+		}
+
+		// Glob sorts temp10 before temp2, sort naturally by label instead, so Core 2 goes before Core 10.
+		sort.SliceStable(chipSensors, func(i, j int) bool { return naturalLess(chipSensors[i].Label, chipSensors[j].Label) })
+
+		sensors = append(sensors, chipSensors...)
+	}
+
+	return sensors, nil
+}
+
+// MatchAny returns true if s matches one of globs or globs list is empty.
+func MatchAny(globs []string, s string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+
+	for _, glob := range globs {
+		if matched, _ := filepath.Match(glob, s); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadHwmonValue reads raw integer value of hwmon input file.
+func ReadHwmonValue(file string) (int64, error) {
+	b, err := os.ReadFile(file)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// naturalLess compares strings so that numbers inside them are compared by value.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+
+		if da != "" && db != "" {
+			na, _ := strconv.Atoi(da)
+			nb, _ := strconv.Atoi(db)
+
+			if na != nb {
+				return na < nb
+			}
+
+			a, b = a[len(da):], b[len(db):]
+
+			continue
+		}
+
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) < len(b)
+}
+
+// leadingDigits returns leading decimal digits of s.
+func leadingDigits(s string) string {
+	i := 0
+
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return s[:i]
+}
@@ -1,13 +1,23 @@
 package lib
 
 import (
+	"fmt"
 	"log"
 	"reflect"
 	"regexp"
+	"strings"
+	"sync"
 
 	"go.i3wm.org/i3"
 )
 
+// focusedWindow is i3 id of currently focused window, focusedWorkspace is name of currently focused workspace.
+var (
+	focusedWindow    i3.NodeID
+	focusedWorkspace string
+	focusMu          sync.Mutex
+)
+
 type WinList struct {
 	Instance Collection
 	Class    Collection
@@ -18,6 +28,7 @@ type WinList struct {
 var WL WinList
 
 // UpdateI3WinList subsribes to all window-related events and send them to I3EventParser() for more detailed parsing.
+// Workspace events are used to notice that focus moved to empty workspace.
 func (c *MyConfig) UpdateI3WinList() {
 	// TODO: Здесь получить начальный список окон, управляемых i3wm (через GetTree()) и сложить их атрибутику в переменную WL
 	Tree, err := i3.GetTree()
@@ -28,11 +39,47 @@ func (c *MyConfig) UpdateI3WinList() {
 
 	c.ExtractProps(Tree.Root)
 
-	i3wm := i3.Subscribe(i3.WindowEventType)
+	workspaces, err := i3.GetWorkspaces()
+
+	if err != nil {
+		log.Printf("Unable to get list of workspaces: %s", err)
+	}
+
+	for _, w := range workspaces {
+		if w.Focused {
+			focusMu.Lock()
+			focusedWorkspace = w.Name
+			focusMu.Unlock()
+		}
+	}
+
+	if Tree.Root != nil {
+		c.WindowFocused(Tree.Root.FindFocused(func(n *i3.Node) bool { return n.Focused && n.Window != 0 }))
+	}
+
+	i3wm := i3.Subscribe(i3.WindowEventType, i3.WorkspaceEventType)
 
 	for i3wm.Next() {
-		e := i3wm.Event().(*i3.WindowEvent)
-		go c.I3EventParser(e)
+		switch e := i3wm.Event().(type) {
+		case *i3.WindowEvent:
+			// Focus and title updates depend on order of events, so they are handled right here, one by one.
+			c.I3FocusEvent(e)
+
+			go c.I3EventParser(e)
+
+		case *i3.WorkspaceEvent:
+			// Workspace focus event comes before focus event of window on it, so time is charged to right workspace.
+			if e.Change == "focus" || (e.Change == "rename" && e.Current.Focused) {
+				focusMu.Lock()
+				focusedWorkspace = e.Current.Name
+				focusMu.Unlock()
+			}
+
+			// There is no window focus event if focused workspace has no windows.
+			if e.Change == "focus" && len(e.Current.Nodes) == 0 && len(e.Current.FloatingNodes) == 0 {
+				c.WindowFocused(nil)
+			}
+		}
 	}
 
 	log.Fatal(i3wm.Close())
@@ -103,6 +150,55 @@ func (c *MyConfig) I3EventParser(e *i3.WindowEvent) {
 	}
 }
 
+// I3FocusEvent updates blocks that depend on focused window. It must be called from i3 subscription loop only, in
+// order of events.
+func (c *MyConfig) I3FocusEvent(e *i3.WindowEvent) {
+	switch e.Change {
+	case "close":
+		focusMu.Lock()
+		closed := e.Container.ID == focusedWindow
+		focusMu.Unlock()
+
+		if closed {
+			c.WindowFocused(nil)
+		}
+
+	case "focus":
+		if e.Container.Focused {
+			c.WindowFocused(&e.Container)
+		}
+
+	case "title":
+		if c.WindowTitle.Enabled && e.Container.Focused {
+			c.UpdateWindowTitle(&e.Container)
+		}
+	}
+}
+
+// WindowFocused notifies blocks that depend on focused window about focus change. If nothing is focused n is nil. It
+// must be called from i3 subscription loop only, so focus changes are applied in order of events.
+func (c *MyConfig) WindowFocused(n *i3.Node) {
+	focusMu.Lock()
+
+	if n == nil {
+		focusedWindow = 0
+	} else {
+		focusedWindow = n.ID
+	}
+
+	workspace := focusedWorkspace
+
+	focusMu.Unlock()
+
+	if c.WindowTitle.Enabled {
+		c.UpdateWindowTitle(n)
+	}
+
+	if c.FocusTime.Enabled {
+		FT.Switch(n, workspace)
+	}
+}
+
 // interfaceToUint превращает данный интерфейс в Uint64.
 // Если может, конечно :) .
 func interfaceToUint64(iface interface{}) uint64 {
@@ -209,6 +305,21 @@ func FindWindowClass(regexpStr string) bool {
 	return found
 }
 
+// RunI3Command runs given command(s) via i3 ipc. Error is returned if at least one of them is unsuccessful.
+func RunI3Command(command string) error {
+	if _, err := i3.RunCommand(command); err != nil {
+		return fmt.Errorf("unable to run i3 command '%s': %w", command, err)
+	}
+
+	return nil
+}
+
+// I3Quote puts given string into double quotes, so i3 command parser treats it as single argument. Backslashes are
+// escaped first, otherwise trailing backslash would escape closing quote.
+func I3Quote(str string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str) + `"`
+}
+
 // ExtractProps fills WL stricture collections with instance name and class name of given node (x11, that is).
 func (c *MyConfig) ExtractProps(n *i3.Node) {
 	var e i3.WindowEvent
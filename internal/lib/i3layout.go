@@ -0,0 +1,151 @@
+package lib
+
+import (
+	"fmt"
+	"log"
+
+	"go.i3wm.org/i3"
+)
+
+// UpdateLayout tracks layout of focused container and number of windows in scratchpad. Layout changes made by key
+// bindings do not produce any dedicated event, so binding events are watched too.
+func (c *MyConfig) UpdateLayout() {
+	c.RefreshLayout()
+
+	i3wm := i3.Subscribe(i3.WindowEventType, i3.WorkspaceEventType, i3.BindingEventType)
+
+	for i3wm.Next() {
+		c.RefreshLayout()
+	}
+
+	log.Fatal(i3wm.Close())
+}
+
+// RefreshLayout gets i3 tree and updates layout block.
+func (c *MyConfig) RefreshLayout() {
+	tree, err := i3.GetTree()
+
+	if err != nil {
+		log.Printf("Unable to get i3 tree: %s", err)
+
+		return
+	}
+
+	layout, scratch := LayoutInfo(tree.Root)
+
+	symbol, exist := c.Layout.Symbols[layout]
+
+	if !exist {
+		symbol = layout
+	}
+
+	if scratch > 0 {
+		symbol += fmt.Sprintf(" %s%d", c.Layout.ScratchpadSymbol, scratch)
+	}
+
+	if c.Values.Layout != symbol {
+		c.Values.Layout = symbol
+		c.Channels.UpdateReady <- true
+	}
+}
+
+// LayoutInfo returns layout of container that holds focused window (or "floating" for floating windows) and number of
+// windows in scratchpad.
+func LayoutInfo(root *i3.Node) (string, int) {
+	var (
+		layout  string
+		scratch int
+	)
+
+	if s := root.FindChild(func(n *i3.Node) bool { return n.Name == "__i3_scratch" }); s != nil {
+		scratch = countWindows(s)
+	}
+
+	focused := root.FindFocused(func(n *i3.Node) bool { return n.Focused })
+
+	switch {
+	case focused == nil:
+		return layout, scratch
+
+	// Empty workspace is focused.
+	case focused.Type == i3.WorkspaceNode:
+		return string(focused.Layout), scratch
+	}
+
+	parent := root.FindChild(func(n *i3.Node) bool {
+		for _, node := range n.Nodes {
+			if node.ID == focused.ID {
+				return true
+			}
+		}
+
+		for _, node := range n.FloatingNodes {
+			if node.ID == focused.ID {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	switch {
+	case parent == nil:
+		layout = string(focused.Layout)
+	case parent.Type == i3.FloatingCon:
+		layout = "floating"
+	default:
+		layout = string(parent.Layout)
+	}
+
+	return layout, scratch
+}
+
+// countWindows returns number of x11 windows in given sub-tree.
+func countWindows(n *i3.Node) int {
+	var count int
+
+	if n.Window != 0 {
+		count++
+	}
+
+	for _, node := range n.Nodes {
+		count += countWindows(node)
+	}
+
+	for _, node := range n.FloatingNodes {
+		count += countWindows(node)
+	}
+
+	return count
+}
+
+// LayoutHandler runs i3 command bound to given mouse button click on layout block.
+func (c *MyConfig) LayoutHandler(e ClickEvent) {
+	var command string
+
+	switch e.Button {
+	case 1:
+		command = c.Layout.LeftClick
+	case 2:
+		command = c.Layout.MiddleClick
+	case 3:
+		command = c.Layout.RightClick
+	case 4:
+		command = c.Layout.WheelUp
+	case 5:
+		command = c.Layout.WheelDown
+	}
+
+	if command == "" {
+		return
+	}
+
+	if err := RunI3Command(command); err != nil {
+		log.Print(err)
+
+		return
+	}
+
+	// Commands run via ipc do not produce binding events, so update block by ourselves.
+	c.RefreshLayout()
+}
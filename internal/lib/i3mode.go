@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"html"
+	"log"
+
+	"go.i3wm.org/i3"
+)
+
+// UpdateBindingMode subscribes to i3 mode events and keeps name of currently active binding mode.
+func (c *MyConfig) UpdateBindingMode() {
+	i3wm := i3.Subscribe(i3.ModeEventType)
+
+	for i3wm.Next() {
+		e := i3wm.Event().(*i3.ModeEvent)
+		mode := e.Change
+
+		// Mode name goes to pango markup as is, so escape it unless i3 says that it is markup already.
+		if !e.PangoMarkup {
+			mode = html.EscapeString(mode)
+		}
+
+		if c.Values.BindingMode != mode {
+			c.Values.BindingMode = mode
+			c.Channels.UpdateReady <- true
+		}
+	}
+
+	log.Fatal(i3wm.Close())
+}
+
+// BindingModeColors returns text and background colors for given binding mode.
+func (c *MyConfig) BindingModeColors(mode string) (string, string) {
+	var (
+		color      = c.BindingMode.Color
+		background = c.BindingMode.Background
+	)
+
+	// Mode name is stored pango-escaped, but configured as is.
+	if m, exist := c.BindingMode.Modes[html.UnescapeString(mode)]; exist {
+		if m.Color != "" {
+			color = m.Color
+		}
+
+		if m.Background != "" {
+			background = m.Background
+		}
+	}
+
+	return color, background
+}
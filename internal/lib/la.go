@@ -1,13 +1,27 @@
 package lib
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
-
-	"github.com/shirou/gopsutil/load"
 )
 
-// UpdateLaStats вытаскивает показание LA за последнюю минуту.
+// LoadAvg is load average for 1, 5 and 15 minutes, optionally divided by number of online cpus, and number of
+// running and total processes.
+type LoadAvg struct {
+	Load1   float64
+	Load5   float64
+	Load15  float64
+	Running int
+	Total   int
+}
+
+// UpdateLaStats читает LA за 1, 5 и 15 минут и число процессов из /proc/loadavg.
 func (c *MyConfig) UpdateLaStats() {
 	var (
 		InitialDelay       = 100 * time.Millisecond
@@ -22,13 +36,143 @@ func (c *MyConfig) UpdateLaStats() {
 			ticker.Reset(Delay)
 		}
 
-		l, _ := load.Avg()
+		l, err := ReadLoadAvg(c.LA.File)
+
+		if err != nil {
+			log.Printf("Unable to get load average from %s: %s", c.LA.File, err)
+
+			continue
+		}
+
+		// Cpus can be hotplugged, so they are counted every time.
+		if c.LA.PerCPU {
+			cpus := float64(OnlineCPUs(c.LA.CPUOnlineFile))
 
-		lav := fmt.Sprintf("%.2f", l.Load1)
+			l.Load1 /= cpus
+			l.Load5 /= cpus
+			l.Load15 /= cpus
+		}
+
+		str := c.FormatLa(l)
+		color, urgent := c.LaColor(l)
 
-		if c.Values.La != lav {
-			c.Values.La = lav
+		if c.Values.La != str || c.Values.LaColor != color || c.Values.LaUrgent != urgent {
+			c.Values.La = str
+			c.Values.LaColor = color
+			c.Values.LaUrgent = urgent
 			c.Channels.UpdateReady <- true
 		}
 	}
 }
+
+// ReadLoadAvg parses /proc/loadavg, e.g. "0.52 0.58 0.59 2/1234 5678".
+func ReadLoadAvg(file string) (LoadAvg, error) {
+	var l LoadAvg
+
+	b, err := os.ReadFile(file)
+
+	if err != nil {
+		return l, err
+	}
+
+	fields := strings.Fields(string(b))
+
+	if len(fields) < 4 {
+		return l, errors.New("malformed load average " + strconv.Quote(strings.TrimSpace(string(b)))) //nolint: err113
+	}
+
+	loads := []*float64{&l.Load1, &l.Load5, &l.Load15}
+
+	for i, load := range loads {
+		if *load, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return l, err
+		}
+	}
+
+	running, total, _ := strings.Cut(fields[3], "/")
+
+	if l.Running, err = strconv.Atoi(running); err != nil {
+		return l, err
+	}
+
+	if l.Total, err = strconv.Atoi(total); err != nil {
+		return l, err
+	}
+
+	return l, nil
+}
+
+// OnlineCPUs returns number of online cpus from cpu list like "0-3,6,8-11". If list can not be read, number of cpus
+// available to process is returned.
+func OnlineCPUs(file string) int {
+	var count int
+
+	s, err := os.ReadFile(file)
+
+	if err != nil {
+		return runtime.NumCPU()
+	}
+
+	for _, r := range strings.Split(strings.TrimSpace(string(s)), ",") {
+		from, to, isRange := strings.Cut(r, "-")
+
+		if !isRange {
+			to = from
+		}
+
+		f, err1 := strconv.Atoi(from)
+		t, err2 := strconv.Atoi(to)
+
+		if err1 != nil || err2 != nil || t < f {
+			return runtime.NumCPU()
+		}
+
+		count += t - f + 1
+	}
+
+	return max(count, 1)
+}
+
+// FormatLa returns load average for 1 minute or for 1, 5 and 15 minutes, trend arrow and process counts.
+func (c *MyConfig) FormatLa(l LoadAvg) string {
+	str := fmt.Sprintf("%.2f", l.Load1)
+
+	if c.LA.ShowAll {
+		str = fmt.Sprintf("%.2f %.2f %.2f", l.Load1, l.Load5, l.Load15)
+	}
+
+	if c.LA.Trend {
+		str += " " + LaTrend(l, *c.LA.TrendDelta)
+	}
+
+	if c.LA.ShowProcs {
+		str += fmt.Sprintf(" %d/%d", l.Running, l.Total)
+	}
+
+	return str
+}
+
+// LaTrend compares 1 and 15 minutes load average: rising load is ↑, falling is ↓, otherwise →.
+func LaTrend(l LoadAvg, delta float64) string {
+	switch {
+	case l.Load1-l.Load15 > delta:
+		return "↑"
+	case l.Load15-l.Load1 > delta:
+		return "↓"
+	default:
+		return "→"
+	}
+}
+
+// LaColor returns block color according to 1 minute load average and whether critical threshold is reached. Zero
+// threshold is not checked.
+func (c *MyConfig) LaColor(l LoadAvg) (string, bool) {
+	switch {
+	case c.LA.Critical > 0 && l.Load1 >= c.LA.Critical:
+		return c.LA.CriticalColor, true
+	case c.LA.Warning > 0 && l.Load1 >= c.LA.Warning:
+		return c.LA.WarningColor, false
+	default:
+		return c.LA.Color, false
+	}
+}
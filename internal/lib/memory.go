@@ -1,17 +1,44 @@
 package lib
 
 import (
+	"bufio"
+	"fmt"
+	"html"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/mem"
 )
 
-// Mem struct with mem stats.
+// Mem struct with mem stats, all sizes are in bytes.
 type Mem struct {
-	Usedpct uint64
-	Shared  uint64
-	Swap    uint64
+	Total     uint64
+	Used      uint64
+	Usedpct   uint64
+	Available uint64
+	Shared    uint64
+	Cached    uint64
+	Buffers   uint64
+	Dirty     uint64
+	Swap      uint64
+	SwapTotal uint64
+
+	// Original and compressed size of data in zram devices and memory used by them.
+	ZramOrig  uint64
+	ZramCompr uint64
+	ZramUsed  uint64
+
+	// Size of zswap pool and size of pages stored in it.
+	Zswap    uint64
+	Zswapped uint64
+
+	// Process with largest resident set size.
+	TopName string
+	TopRSS  uint64
 }
 
 // UpdateMemStats parses mem info stats.
@@ -24,6 +51,8 @@ func (c *MyConfig) UpdateMemStats() {
 	)
 
 	for range ticker.C {
+		var m Mem
+
 		if Delay == InitialDelay {
 			Delay = LoopIterationDelay
 			ticker.Reset(Delay)
@@ -38,28 +67,231 @@ func (c *MyConfig) UpdateMemStats() {
 			continue
 		}
 
-		sw, err := mem.SwapMemory()
+		m.Total = v.Total
+		m.Used = v.Used
+		m.Usedpct = uint64(v.UsedPercent)
+		m.Available = v.Available
+		m.Shared = v.Shared
+		m.Cached = v.Cached
+		m.Buffers = v.Buffers
+		m.Dirty = v.Dirty
+
+		if strings.Contains(c.Mem.Format, "{swap") {
+			sw, err := mem.SwapMemory()
+
+			if err != nil {
+				log.Printf("Unable to get swap statistics: %s", err)
+				time.Sleep(1 * time.Second)
+
+				continue
+			}
+
+			m.Swap = sw.Used
+			m.SwapTotal = sw.Total
+		}
+
+		if strings.Contains(c.Mem.Format, "{zram") {
+			m.ZramOrig, m.ZramCompr, m.ZramUsed = ReadZramStats(c.Mem.SysBlockDir)
+		}
+
+		if strings.Contains(c.Mem.Format, "{zswap") {
+			m.Zswap, m.Zswapped = ReadZswapStats(filepath.Join(c.Mem.ProcDir, "meminfo"))
+		}
+
+		if strings.Contains(c.Mem.Format, "{top") {
+			m.TopName, m.TopRSS = TopRSSProcess(c.Mem.ProcDir)
+		}
+
+		// Compare rendered string, so byte-level changes of counters that are not shown do not cause redraw.
+		str := c.FormatMemory(m)
+
+		if c.Values.Memory != str {
+			c.Values.Memory = str
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// FormatMemory substitutes placeholders of configured format with memory stats.
+func (c *MyConfig) FormatMemory(m Mem) string {
+	var (
+		zramRatio  float64
+		zswapRatio float64
+		swapPct    uint64
+	)
+
+	if m.ZramCompr > 0 {
+		zramRatio = float64(m.ZramOrig) / float64(m.ZramCompr)
+	}
+
+	if m.Zswap > 0 {
+		zswapRatio = float64(m.Zswapped) / float64(m.Zswap)
+	}
+
+	if m.SwapTotal > 0 {
+		swapPct = m.Swap * 100 / m.SwapTotal
+	}
+
+	top := "-"
+
+	if m.TopName != "" {
+		top = fmt.Sprintf("%s %s", html.EscapeString(m.TopName), HumanBytes(m.TopRSS))
+	}
+
+	r := strings.NewReplacer(
+		"{used_pct}", strconv.FormatUint(m.Usedpct, 10),
+		"{used}", HumanBytes(m.Used),
+		"{total}", HumanBytes(m.Total),
+		"{available}", HumanBytes(m.Available),
+		"{shared}", HumanBytes(m.Shared),
+		"{cached}", HumanBytes(m.Cached),
+		"{buffers}", HumanBytes(m.Buffers),
+		"{dirty}", HumanBytes(m.Dirty),
+		"{swap_pct}", strconv.FormatUint(swapPct, 10),
+		"{swap}", HumanBytes(m.Swap),
+		"{zram_ratio}", fmt.Sprintf("%.1f", zramRatio),
+		"{zram_orig}", HumanBytes(m.ZramOrig),
+		"{zram}", HumanBytes(m.ZramUsed),
+		"{zswap_ratio}", fmt.Sprintf("%.1f", zswapRatio),
+		"{zswapped}", HumanBytes(m.Zswapped),
+		"{zswap}", HumanBytes(m.Zswap),
+		"{top}", top,
+	)
+
+	return r.Replace(c.Mem.Format)
+}
+
+// ReadZramStats sums original data size, compressed data size and total used memory of all zram devices.
+func ReadZramStats(dir string) (uint64, uint64, uint64) {
+	var orig, compr, used uint64
+
+	devices, _ := filepath.Glob(filepath.Join(dir, "zram*"))
+
+	for _, device := range devices {
+		s, err := ReadSysfsString(device, "mm_stat")
 
 		if err != nil {
-			log.Printf("Unable to get swap statistics: %s", err)
-			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		fields := strings.Fields(s)
 
+		if len(fields) < 3 {
 			continue
 		}
 
-		if c.Mem.ShowSwap {
-			if c.Values.Memory.Usedpct != uint64(v.UsedPercent) || c.Values.Memory.Shared != v.Shared/1024/1024 || c.Values.Memory.Swap != v.SwapTotal-v.SwapFree {
-				c.Values.Memory.Usedpct = uint64(v.UsedPercent)
-				c.Values.Memory.Shared = v.Shared / 1024 / 1024
-				c.Values.Memory.Swap = sw.Used / 1024 / 1024
-				c.Channels.UpdateReady <- true
-			}
-		} else {
-			if c.Values.Memory.Usedpct != uint64(v.UsedPercent) || c.Values.Memory.Shared != v.Shared/1024/1024 {
-				c.Values.Memory.Usedpct = uint64(v.UsedPercent)
-				c.Values.Memory.Shared = v.Shared / 1024 / 1024
-				c.Channels.UpdateReady <- true
-			}
+		o, _ := strconv.ParseUint(fields[0], 10, 64)
+		cd, _ := strconv.ParseUint(fields[1], 10, 64)
+		u, _ := strconv.ParseUint(fields[2], 10, 64)
+
+		orig += o
+		compr += cd
+		used += u
+	}
+
+	return orig, compr, used
+}
+
+// ReadZswapStats returns zswap pool size and size of pages stored in it from meminfo, kernels older than 5.19 do
+// not report them, so zeroes are returned.
+func ReadZswapStats(meminfo string) (uint64, uint64) {
+	var pool, stored uint64
+
+	f, err := os.Open(meminfo)
+
+	if err != nil {
+		log.Printf("Unable to read %s: %s", meminfo, err)
+
+		return 0, 0
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		// Values are in kB.
+		v, _ := strconv.ParseUint(fields[1], 10, 64)
+
+		switch fields[0] {
+		case "Zswap:":
+			pool = v * 1024
+		case "Zswapped:":
+			stored = v * 1024
+		}
+	}
+
+	return pool, stored
+}
+
+// TopRSSProcess returns name and resident set size of process that uses most memory.
+func TopRSSProcess(proc string) (string, uint64) {
+	var (
+		topName string
+		topRSS  uint64
+		page    = uint64(os.Getpagesize())
+	)
+
+	entries, err := os.ReadDir(proc)
+
+	if err != nil {
+		log.Printf("Unable to read %s: %s", proc, err)
+
+		return "", 0
+	}
+
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+
+		dir := filepath.Join(proc, e.Name())
+
+		// Process can exit while we read it, so errors are ignored.
+		statm, err := ReadSysfsString(dir, "statm")
+
+		if err != nil {
+			continue
 		}
+
+		fields := strings.Fields(statm)
+
+		if len(fields) < 2 {
+			continue
+		}
+
+		rss, _ := strconv.ParseUint(fields[1], 10, 64)
+
+		if rss*page <= topRSS {
+			continue
+		}
+
+		name, err := ReadSysfsString(dir, "comm")
+
+		if err != nil {
+			continue
+		}
+
+		topName, topRSS = name, rss*page
+	}
+
+	return topName, topRSS
+}
+
+// HumanBytes formats size in binary units: GiB, MiB or KiB.
+func HumanBytes(b uint64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGiB", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%.0fMiB", float64(b)/(1<<20))
+	default:
+		return fmt.Sprintf("%.0fKiB", float64(b)/(1<<10))
 	}
 }
@@ -0,0 +1,171 @@
+package lib
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"strings"
+)
+
+// UpdateMicVolume tracks volume and mute state of default source and whether some application records from it.
+func (c *MyConfig) UpdateMicVolume() {
+	PactlSubscribe([]string{"source", "source-output", "server"}, c.RefreshMicVolume, c.MicUnavailable)
+}
+
+// RefreshMicVolume gets state of default source and its recording streams and renders it for i3bar.
+func (c *MyConfig) RefreshMicVolume() error {
+	var outputs []PactlStream
+
+	source, err := PactlDefaultSource()
+
+	if err != nil {
+		return fmt.Errorf("unable to get default pulseaudio source: %w", err)
+	}
+
+	if err := PactlList("source-outputs", &outputs); err != nil {
+		return fmt.Errorf("unable to get pulseaudio source outputs: %w", err)
+	}
+
+	var apps []string
+
+	for _, o := range outputs {
+		if o.Source != source.Index || PactlIgnored(o, c.MicVolumePa.IgnoreApps) {
+			continue
+		}
+
+		apps = append(apps, PactlAppName(o))
+	}
+
+	str := c.RenderMicVolume(PactlAvgVolume(source.Volume), source.Mute, apps)
+
+	c.SetMicVolume(str, len(apps) > 0)
+
+	return nil
+}
+
+// MicUnavailable shows on mic block that source state can not be read.
+func (c *MyConfig) MicUnavailable() {
+	str := fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+		c.MicVolumePa.MutedColor,
+		c.MicVolumePa.Background,
+		c.MicVolumePa.SymbolFont,
+		c.MicVolumePa.SymbolFontSize,
+		c.MicVolumePa.MutedSymbol,
+	)
+
+	str += fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>:%s</span>",
+		c.MicVolumePa.MutedColor,
+		c.MicVolumePa.Background,
+		c.MicVolumePa.Font,
+		c.MicVolumePa.FontSize,
+		html.EscapeString(c.MicVolumePa.UnavailableText),
+	)
+
+	c.SetMicVolume(str, false)
+}
+
+// SetMicVolume sets text and in use state of mic block and asks for bar update if they have changed.
+func (c *MyConfig) SetMicVolume(str string, inUse bool) {
+	if c.Values.MicVolume != str || c.Values.MicInUse != inUse {
+		c.Values.MicVolume = str
+		c.Values.MicInUse = inUse
+		c.Channels.UpdateReady <- true
+	}
+}
+
+// RenderMicVolume returns pango-formatted mic block text. Block is highlighted while apps record from source.
+func (c *MyConfig) RenderMicVolume(vol float32, muted bool, apps []string) string {
+	var (
+		symbol = c.MicVolumePa.Symbol
+		color  = c.MicVolumePa.Color
+	)
+
+	switch {
+	case muted:
+		symbol = c.MicVolumePa.MutedSymbol
+		color = c.MicVolumePa.MutedColor
+
+	case len(apps) > 0:
+		color = c.MicVolumePa.InUseColor
+	}
+
+	str := fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+		color,
+		c.MicVolumePa.Background,
+		c.MicVolumePa.SymbolFont,
+		c.MicVolumePa.SymbolFontSize,
+		symbol,
+	)
+
+	str += fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>:%d%%</span>",
+		color,
+		c.MicVolumePa.Background,
+		c.MicVolumePa.Font,
+		c.MicVolumePa.FontSize,
+		int64(math.Round(float64(vol)*100)),
+	)
+
+	if c.MicVolumePa.ShowApps && len(apps) > 0 {
+		str += fmt.Sprintf(
+			"<span color='%s' background='%s' font='%s' size='%s'> %s</span>",
+			color,
+			c.MicVolumePa.Background,
+			c.MicVolumePa.Font,
+			c.MicVolumePa.FontSize,
+			html.EscapeString(strings.Join(apps, ", ")),
+		)
+	}
+
+	return str
+}
+
+// MicHandler changes volume of default source by mouse wheel and toggles its mute state by click.
+func (c *MyConfig) MicHandler() {
+	for e := range c.Channels.MicHandlerChan {
+		var args []string
+
+		switch e.Button {
+		case c.MicVolumePa.MuteButton:
+			args = []string{"set-source-mute", "@DEFAULT_SOURCE@", "toggle"}
+
+		case c.MicVolumePa.WheelUp:
+			source, err := PactlDefaultSource()
+
+			if err != nil {
+				log.Printf("Unable to get default pulseaudio source: %s", err)
+
+				continue
+			}
+
+			vol := int(PactlAvgVolume(source.Volume)*100+0.5) + c.MicVolumePa.Step
+
+			if vol > c.MicVolumePa.MaxVolumeLimit {
+				vol = c.MicVolumePa.MaxVolumeLimit
+			}
+
+			args = []string{"set-source-volume", "@DEFAULT_SOURCE@", fmt.Sprintf("%d%%", vol)}
+
+		case c.MicVolumePa.WheelDown:
+			// Without "--" pactl treats negative volume as unknown option.
+			args = []string{"--", "set-source-volume", "@DEFAULT_SOURCE@", fmt.Sprintf("-%d%%", c.MicVolumePa.Step)}
+
+		case 3:
+			c.Channels.RunChan <- c.MicVolumePa.RightClickCmd
+
+			continue
+
+		default:
+			continue
+		}
+
+		// Block itself is updated by pactl subscribe events.
+		if _, err := Pactl(args...); err != nil {
+			log.Print(err)
+		}
+	}
+}
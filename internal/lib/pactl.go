@@ -0,0 +1,210 @@
+package lib
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// pactlTimeout limits time of single pactl invocation, hung pulseaudio server must not hang our modules.
+	pactlTimeout = 3 * time.Second
+
+	// pactlMinVersion is first version of pactl that supports json output.
+	pactlMinVersion = 16
+)
+
+// PactlVolume is volume of one channel as reported by pactl.
+type PactlVolume struct {
+	Value        int    `json:"value"`
+	ValuePercent string `json:"value_percent"`
+}
+
+// PactlDevice is sink or source as reported by "pactl -f json list sinks|sources".
+type PactlDevice struct {
+	Index       int                    `json:"index"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Mute        bool                   `json:"mute"`
+	Volume      map[string]PactlVolume `json:"volume"`
+}
+
+// PactlStream is sink input or source output as reported by "pactl -f json list sink-inputs|source-outputs".
+type PactlStream struct {
+	Index      int                    `json:"index"`
+	Sink       int                    `json:"sink"`
+	Source     int                    `json:"source"`
+	Mute       bool                   `json:"mute"`
+	Corked     bool                   `json:"corked"`
+	Volume     map[string]PactlVolume `json:"volume"`
+	Properties map[string]string      `json:"properties"`
+}
+
+// Pactl runs pactl with given arguments and returns its stdout.
+func Pactl(args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pactlTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "pactl", args...).Output()
+
+	// Old pactl says about unknown option in stderr only.
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("unable to run pactl %s: %w: %s", strings.Join(args, " "), err,
+			strings.TrimSpace(string(exitErr.Stderr)))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to run pactl %s: %w", strings.Join(args, " "), err)
+	}
+
+	return out, nil
+}
+
+// PactlList returns decoded json output of "pactl list <what>".
+func PactlList(what string, v any) error {
+	out, err := Pactl("-f", "json", "list", what)
+
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("unable to decode output of pactl list %s: %w", what, err)
+	}
+
+	return nil
+}
+
+// PactlDefaultSource returns default source.
+func PactlDefaultSource() (PactlDevice, error) {
+	var sources []PactlDevice
+
+	out, err := Pactl("get-default-source")
+
+	if err != nil {
+		return PactlDevice{}, err
+	}
+
+	name := strings.TrimSpace(string(out))
+
+	if err := PactlList("sources", &sources); err != nil {
+		return PactlDevice{}, err
+	}
+
+	for _, s := range sources {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return PactlDevice{}, fmt.Errorf("default source %s not found", name) //nolint: err113
+}
+
+// PactlAppName returns application name of given stream.
+func PactlAppName(s PactlStream) string {
+	for _, key := range []string{"application.name", "application.process.binary", "media.name"} {
+		if name := s.Properties[key]; name != "" {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("#%d", s.Index)
+}
+
+// PactlIgnored returns true if application name of given stream is in ignore list.
+func PactlIgnored(s PactlStream, ignore []string) bool {
+	return slices.Contains(ignore, PactlAppName(s)) || slices.Contains(ignore, s.Properties["application.id"])
+}
+
+// PactlAvgVolume returns average volume of all channels, 1.0 is 100%.
+func PactlAvgVolume(volume map[string]PactlVolume) float32 {
+	var sum float32
+
+	if len(volume) == 0 {
+		return 0
+	}
+
+	for _, v := range volume {
+		pct, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(v.ValuePercent, "%")), 32)
+
+		if err != nil {
+			continue
+		}
+
+		sum += float32(pct) / 100
+	}
+
+	return sum / float32(len(volume))
+}
+
+// PactlSubscribe runs "pactl subscribe" and calls fn on every event about one of given kinds of objects (sink, source,
+// sink-input, source-output, server, ...). If pactl exits, it is restarted after a while, so fn is called on restart
+// too: objects could change while we were not watching. If pactl can not be run or fn fails, unavailable is called
+// and error is logged once, until fn succeeds again.
+func PactlSubscribe(kinds []string, fn func() error, unavailable func()) {
+	var failed bool
+
+	fail := func(err error) {
+		if !failed {
+			log.Printf("Pulseaudio state is not available, pactl %d or newer is required: %s", pactlMinVersion, err)
+		}
+
+		failed = true
+
+		unavailable()
+	}
+
+	refresh := func() {
+		if err := fn(); err != nil {
+			fail(err)
+
+			return
+		}
+
+		failed = false
+	}
+
+	for {
+		cmd := exec.Command("pactl", "subscribe")
+		stdout, err := cmd.StdoutPipe()
+
+		if err != nil {
+			log.Printf("Unable to get stdout of pactl subscribe: %s", err)
+
+			return
+		}
+
+		if err := cmd.Start(); err != nil {
+			fail(fmt.Errorf("unable to run pactl subscribe: %w", err))
+		} else {
+			refresh()
+
+			scanner := bufio.NewScanner(stdout)
+
+			// Lines look like: Event 'change' on source-output #42
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+
+				if len(fields) >= 4 && slices.Contains(kinds, fields[3]) {
+					refresh()
+				}
+			}
+
+			if err := cmd.Wait(); err != nil {
+				log.Printf("pactl subscribe exited: %s", err)
+			}
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
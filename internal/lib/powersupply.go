@@ -0,0 +1,160 @@
+package lib
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/distatus/battery"
+)
+
+// PowerSupply is battery or AC adapter found in sysfs. Energy is in mWh, power in mW, zero means that power supply does
+// not report value.
+type PowerSupply struct {
+	Name         string
+	Type         string
+	Scope        string
+	Online       bool
+	Status       string
+	Capacity     int
+	EnergyNow    float64
+	EnergyFull   float64
+	EnergyDesign float64
+	PowerNow     float64
+	CycleCount   int
+}
+
+// ScanPowerSupplies returns all power supplies found in given sysfs dir, usually /sys/class/power_supply, sorted by
+// name. It is cheap enough to be called on every update, so hotplugged batteries are picked up.
+func ScanPowerSupplies(root string) ([]PowerSupply, error) {
+	var supplies []PowerSupply
+
+	entries, err := os.ReadDir(root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		dir := filepath.Join(root, e.Name())
+
+		t, err := ReadSysfsString(dir, "type")
+
+		// Not a power supply or it has gone while we were reading.
+		if err != nil {
+			continue
+		}
+
+		s := PowerSupply{Name: e.Name(), Type: t}
+		s.Scope, _ = ReadSysfsString(dir, "scope")
+
+		if online, err := ReadSysfsFloat(dir, "online"); err == nil {
+			s.Online = online > 0
+		}
+
+		if t == "Battery" {
+			s.ReadBattery(dir)
+		}
+
+		supplies = append(supplies, s)
+	}
+
+	sort.Slice(supplies, func(i, j int) bool { return supplies[i].Name < supplies[j].Name })
+
+	return supplies, nil
+}
+
+// ReadBattery reads charge, capacity, power and health of battery from given sysfs dir. Drivers report either energy
+// (µWh, µW) or charge (µAh, µA), charge is converted to energy using voltage.
+func (s *PowerSupply) ReadBattery(dir string) {
+	s.Status, _ = ReadSysfsString(dir, "status")
+
+	if capacity, err := ReadSysfsFloat(dir, "capacity"); err == nil {
+		s.Capacity = int(capacity)
+	}
+
+	if cycles, err := ReadSysfsFloat(dir, "cycle_count"); err == nil {
+		s.CycleCount = int(cycles)
+	}
+
+	voltage, err := ReadSysfsFloat(dir, "voltage_min_design")
+
+	if err != nil {
+		voltage, _ = ReadSysfsFloat(dir, "voltage_now")
+	}
+
+	for _, f := range []struct {
+		dst    *float64
+		energy string
+		charge string
+	}{
+		{&s.EnergyNow, "energy_now", "charge_now"},
+		{&s.EnergyFull, "energy_full", "charge_full"},
+		{&s.EnergyDesign, "energy_full_design", "charge_full_design"},
+	} {
+		if energy, err := ReadSysfsFloat(dir, f.energy); err == nil {
+			*f.dst = energy / 1000
+		} else if charge, err := ReadSysfsFloat(dir, f.charge); err == nil {
+			*f.dst = charge * voltage / 1e9
+		}
+	}
+
+	// Some drivers, e.g. of HID peripherals, report full capacity and percents, but not current charge.
+	if s.EnergyNow == 0 && s.EnergyFull > 0 {
+		s.EnergyNow = s.EnergyFull * float64(s.Capacity) / 100
+	}
+
+	if power, err := ReadSysfsFloat(dir, "power_now"); err == nil {
+		s.PowerNow = power / 1000
+	} else if current, err := ReadSysfsFloat(dir, "current_now"); err == nil {
+		voltage, _ := ReadSysfsFloat(dir, "voltage_now")
+		s.PowerNow = current * voltage / 1e9
+	}
+
+	// Some drivers report negative current while discharging.
+	s.PowerNow = math.Abs(s.PowerNow)
+}
+
+// IsAC returns true for online AC adapter or USB power source.
+func (s *PowerSupply) IsAC() bool {
+	return s.Type != "Battery" && s.Online
+}
+
+// HasEnergy returns true if driver reports energy or charge of battery, not only percents.
+func (s *PowerSupply) HasEnergy() bool {
+	return s.EnergyFull > 0
+}
+
+// Battery converts battery power supply to battery.Battery. If driver does not report energy, only charge in percents
+// is known and it is stored as if full capacity is 100 mWh, without charge rate.
+func (s *PowerSupply) Battery() *battery.Battery {
+	var b battery.Battery
+
+	switch s.Status {
+	case "Charging":
+		b.State.Raw = battery.Charging
+	case "Discharging":
+		b.State.Raw = battery.Discharging
+	case "Empty":
+		b.State.Raw = battery.Empty
+	case "Full":
+		b.State.Raw = battery.Full
+	case "Not charging":
+		b.State.Raw = battery.Idle
+	default:
+		b.State.Raw = battery.Unknown
+	}
+
+	if s.HasEnergy() {
+		b.Current = s.EnergyNow
+		b.Full = s.EnergyFull
+		b.Design = s.EnergyDesign
+		b.ChargeRate = s.PowerNow
+	} else {
+		b.Current = float64(s.Capacity)
+		b.Full = 100
+	}
+
+	return &b
+}
@@ -0,0 +1,184 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PSI is pressure stall information of one resource: avg10 share of time in percents when some or all tasks were
+// stalled. Cpu pressure of older kernels has no full line.
+type PSI struct {
+	Some    float64
+	Full    float64
+	HasFull bool
+}
+
+// psiHook tracks how long pressure of hooked resource stays over threshold.
+type psiHook struct {
+	since time.Time
+	fired bool
+}
+
+// psiLabels are short names of resources shown in block.
+var psiLabels = map[string]string{
+	"cpu":    "CPU",
+	"memory": "MEM",
+	"io":     "IO",
+}
+
+// UpdatePSI reads pressure stall information of configured resources.
+func (c *MyConfig) UpdatePSI() {
+	var (
+		InitialDelay       = 100 * time.Millisecond
+		LoopIterationDelay = time.Duration(c.PSI.Interval) * time.Second
+		Delay              = InitialDelay
+		ticker             = time.NewTicker(Delay)
+		hook               psiHook
+		failed             = map[string]bool{}
+	)
+
+	for range ticker.C {
+		var (
+			items  []string
+			urgent bool
+		)
+
+		if Delay == InitialDelay {
+			Delay = LoopIterationDelay
+			ticker.Reset(Delay)
+		}
+
+		for _, resource := range c.PSI.Resources {
+			p, err := ReadPSI(filepath.Join(c.PSI.ProcDir, resource))
+
+			// Kernel without psi support will fail every time, so log it once.
+			if err != nil {
+				if !failed[resource] {
+					log.Printf("Unable to read %s pressure: %s", resource, err)
+				}
+
+				failed[resource] = true
+
+				continue
+			}
+
+			failed[resource] = false
+
+			if resource == c.PSI.Hook.Resource {
+				hook.Check(c, p)
+			}
+
+			value := p.Some
+			text := fmt.Sprintf("%s %.1f", psiLabels[resource], p.Some)
+
+			if c.PSI.ShowFull && p.HasFull {
+				value = max(value, p.Full)
+				text += fmt.Sprintf("/%.1f", p.Full)
+			}
+
+			color := c.PSI.Color
+
+			switch {
+			case value >= c.PSI.Critical:
+				color = c.PSI.CriticalColor
+				urgent = true
+			case value >= c.PSI.Warning:
+				color = c.PSI.WarningColor
+			}
+
+			items = append(items, fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				color,
+				c.PSI.Background,
+				c.PSI.Font,
+				c.PSI.FontSize,
+				html.EscapeString(text),
+			))
+		}
+
+		str := strings.Join(items, fmt.Sprintf(
+			"<span background='%s' font='%s' size='%s'> </span>",
+			c.PSI.Background,
+			c.PSI.Font,
+			c.PSI.FontSize,
+		))
+
+		if c.Values.PSI != str || c.Values.PSIUrgent != urgent {
+			c.Values.PSI = str
+			c.Values.PSIUrgent = urgent
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// Check runs hook command once pressure stays over threshold for configured duration. Hook is re-armed when pressure
+// drops below threshold.
+func (h *psiHook) Check(c *MyConfig, p PSI) {
+	value := p.Some
+
+	if c.PSI.Hook.Kind == "full" {
+		value = p.Full
+	}
+
+	if len(c.PSI.Hook.Cmd) == 0 || value < c.PSI.Hook.Threshold {
+		h.since = time.Time{}
+		h.fired = false
+
+		return
+	}
+
+	if h.since.IsZero() {
+		h.since = time.Now()
+	}
+
+	if !h.fired && time.Since(h.since) >= time.Duration(c.PSI.Hook.Duration)*time.Second {
+		h.fired = true
+		c.Channels.RunChan <- c.PSI.Hook.Cmd
+	}
+}
+
+// ReadPSI parses avg10 values of some and full lines of /proc/pressure file.
+func ReadPSI(file string) (PSI, error) {
+	var p PSI
+
+	f, err := os.Open(file)
+
+	if err != nil {
+		return p, err
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "avg10=") {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimPrefix(fields[1], "avg10="), 64)
+
+		if err != nil {
+			return p, fmt.Errorf("malformed line %q: %w", scanner.Text(), err)
+		}
+
+		switch fields[0] {
+		case "some":
+			p.Some = v
+		case "full":
+			p.Full = v
+			p.HasFull = true
+		}
+	}
+
+	return p, scanner.Err()
+}
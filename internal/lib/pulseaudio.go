@@ -3,116 +3,385 @@ package lib
 import (
 	"errors"
 	"fmt"
+	"html"
 	"log"
+	"math"
 	"os/exec"
+	"slices"
+	"sync"
 	"time"
 
 	p "github.com/mafik/pulseaudio"
 )
 
-// TODO: https://twin.sh/articles/44/add-a-timeout-to-any-function-in-go timeout pulseaudio calls
+// Pulseaudio client does not notice dead connection: requests hang forever and updates channel is never closed. So
+// every request has timeout and connection is periodically checked by requesting volume.
+const (
+	paTimeout       = 3 * time.Second
+	paCheckInterval = 10 * time.Second
+	paMinBackoff    = 1 * time.Second
+	paMaxBackoff    = 1 * time.Minute
+)
+
+var (
+	errPaUnavailable = errors.New("not connected to pulseaudio server")
+	errPaTimeout     = errors.New("timeout waiting pulseaudio server reply")
+)
+
+// paMu guards c.Values.PA, it is replaced on reconnect while click handler uses it.
+var paMu sync.Mutex
+
+// PaCall runs fn with current pulseaudio client of volume block, see PaClientCall.
+func PaCall[T any](c *MyConfig, fn func(pa *p.Client) (T, error)) (T, error) {
+	paMu.Lock()
+	pa := c.Values.PA
+	paMu.Unlock()
+
+	return PaClientCall(pa, fn)
+}
+
+// PaClientCall runs fn with given pulseaudio client and waits for result no longer than paTimeout. On timeout fn is
+// left running in background, there is no way to cancel request of pulseaudio client.
+func PaClientCall[T any](pa *p.Client, fn func(pa *p.Client) (T, error)) (T, error) {
+	var zero T
+
+	if pa == nil {
+		return zero, errPaUnavailable
+	}
+
+	type result struct {
+		val T
+		err error
+	}
 
-// UpdateVolumeInfo updates info about current Sound Volume.
+	ch := make(chan result, 1)
+
+	go func() {
+		val, err := fn(pa)
+		ch <- result{val, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.val, r.err
+	case <-time.After(paTimeout):
+		return zero, errPaTimeout
+	}
+}
+
+// UpdateVolumeInfo updates info about current Sound Volume. If connection to pulseaudio server lost, block shows
+// unavailable state and connection is re-established with exponential backoff.
 func (c *MyConfig) UpdateVolumeInfo() {
-	var err error
+	backoff := paMinBackoff
+
+	for {
+		if err := c.PaConnect(); err != nil {
+			log.Printf("Unable to connect to pulseaudio server, retry in %s: %s", backoff, err)
+
+			c.VolumeUnavailable()
+			time.Sleep(backoff)
+
+			backoff = min(backoff*2, paMaxBackoff)
+
+			continue
+		}
+
+		backoff = paMinBackoff
+
+		if err := c.WatchVolume(); err != nil {
+			log.Printf("Lost connection to pulseaudio server: %s", err)
+		}
+
+		c.PaDisconnect()
+		c.VolumeUnavailable()
+	}
+}
+
+// WatchVolume updates volume block on every pulseaudio update event until connection looks dead.
+func (c *MyConfig) WatchVolume() error {
+	if err := c.RefreshVolume(); err != nil {
+		return err
+	}
 
-	c.Values.PA, err = p.NewClient()
+	// Subscribe to update notification channel, to get info that volume changed.
+	pulseUpdate, err := PaCall(c, func(pa *p.Client) (<-chan struct{}, error) { return pa.Updates() })
 
-	// It can happen if no pulseaudio server running for current user.
-	// If no server running we have to run one.
 	if err != nil {
-		if err := c.PaReinit(); err != nil {
-			log.Print(err)
+		return fmt.Errorf("unable to subscribe to pulseaudio updates: %w", err)
+	}
+
+	ticker := time.NewTicker(paCheckInterval)
+	defer ticker.Stop()
 
-			return
+	for {
+		select {
+		case _, ok := <-pulseUpdate:
+			if !ok {
+				return errors.New("pulseaudio updates channel closed") //nolint: err113
+			}
+
+		// Periodic refresh is our health check.
+		case <-ticker.C:
+		}
+
+		if err := c.RefreshVolume(); err != nil {
+			return err
 		}
 	}
+}
 
-	vol, err := c.Values.PA.Volume()
+// PaNewClient connects to pulseaudio server and waits for it no longer than paTimeout, connect to wedged socket never
+// returns. Client that is connected after timeout is closed.
+func PaNewClient() (*p.Client, error) {
+	type result struct {
+		pa  *p.Client
+		err error
+	}
+
+	// Unbuffered, so late result is not sent and client is closed.
+	ch := make(chan result)
+	done := make(chan struct{})
+
+	go func() {
+		pa, err := p.NewClient()
+
+		select {
+		case ch <- result{pa, err}:
+		case <-done:
+			if pa != nil {
+				pa.Close()
+			}
+		}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.pa, r.err
+	case <-time.After(paTimeout):
+		close(done)
+
+		return nil, errPaTimeout
+	}
+}
+
+// PaConnect makes client connection to pulseaudio server. If restart_server is set and server does not respond, it is
+// restarted.
+func (c *MyConfig) PaConnect() error {
+	pa, err := PaNewClient()
+
+	if err != nil && c.SimpleVolumePa.RestartServer {
+		log.Printf("Unable to connect to pulseaudio server, restarting it: %s", err)
+
+		if err := c.PaRestartServer(); err != nil {
+			return err
+		}
+
+		pa, err = PaNewClient()
+	}
 
 	if err != nil {
-		log.Printf("Unable get volume from pulseaudio server: %s", err)
+		return fmt.Errorf("unable to make client connection to pulseaudio: %w", err)
+	}
 
-		return
+	paMu.Lock()
+	c.Values.PA = pa
+	paMu.Unlock()
+
+	return nil
+}
+
+// PaDisconnect closes connection to pulseaudio server.
+func (c *MyConfig) PaDisconnect() {
+	paMu.Lock()
+	pa := c.Values.PA
+	c.Values.PA = nil
+	paMu.Unlock()
+
+	if pa != nil {
+		pa.Close()
 	}
+}
 
-	c.Values.SoundVolume = fmt.Sprintf(
+// VolumeUnavailable shows on volume block that sound server is not available.
+func (c *MyConfig) VolumeUnavailable() {
+	str := fmt.Sprintf(
 		"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
-		c.SimpleVolumePa.Color,
+		c.SimpleVolumePa.MutedColor,
 		c.SimpleVolumePa.Background,
 		c.SimpleVolumePa.SymbolFont,
 		c.SimpleVolumePa.SymbolFontSize,
-		c.SimpleVolumePa.Symbol,
+		c.SimpleVolumePa.MutedSymbol,
 	)
 
-	c.Values.SoundVolume += fmt.Sprintf(
-		"<span color='%s' background='%s' font='%s' size='%s'>:%d%%</span>",
-		c.SimpleVolumePa.Color,
+	str += fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>:%s</span>",
+		c.SimpleVolumePa.MutedColor,
 		c.SimpleVolumePa.Background,
 		c.SimpleVolumePa.Font,
 		c.SimpleVolumePa.FontSize,
-		int64(vol*100),
+		html.EscapeString(c.SimpleVolumePa.UnavailableText),
 	)
 
-	c.Channels.UpdateReady <- true
+	if c.Values.SoundVolume != str {
+		c.Values.SoundVolume = str
+		c.Channels.UpdateReady <- true
+	}
+}
 
-	for {
-		// Subscribe to update notification channel, to get info that volume changed.
-		pulseUpdate, err := c.Values.PA.Updates()
+// RefreshVolume gets volume and mute state of default sink and renders them for i3bar.
+func (c *MyConfig) RefreshVolume() error {
+	vol, err := PaCall(c, func(pa *p.Client) (float32, error) { return pa.Volume() })
 
-		if err != nil {
-			log.Printf("Unable to subscribe to pulseaudio updates: %s", err)
+	if err != nil {
+		return fmt.Errorf("unable get volume from pulseaudio server: %w", err)
+	}
+
+	muted, err := PaCall(c, func(pa *p.Client) (bool, error) { return pa.Mute() })
+
+	if err != nil {
+		return fmt.Errorf("unable get mute state from pulseaudio server: %w", err)
+	}
 
-			return
+	var output string
+
+	if c.SimpleVolumePa.ShowOutput {
+		outputs, active, err := c.PaOutputs()
+
+		if err != nil {
+			return fmt.Errorf("unable get list of outputs from pulseaudio server: %w", err)
 		}
 
-		// Rake update events.
-		for range pulseUpdate {
-			vol, err = c.Values.PA.Volume()
+		output = c.OutputName(outputs[active])
+	}
+
+	// Periodic health check must not redraw bar if nothing changed.
+	if str := c.RenderVolume(vol, muted, output); c.Values.SoundVolume != str {
+		c.Values.SoundVolume = str
+		c.Channels.UpdateReady <- true
+	}
 
-			if err != nil {
-				log.Printf("Unable get volume from pulseaudio server: %s", err)
+	return nil
+}
 
-				return
-			}
+// PaOutputs returns list of pulseaudio outputs and index of active one.
+func (c *MyConfig) PaOutputs() ([]p.Output, int, error) {
+	type outputs struct {
+		list   []p.Output
+		active int
+	}
+
+	o, err := PaCall(c, func(pa *p.Client) (outputs, error) {
+		list, active, err := pa.Outputs()
 
-			c.Values.SoundVolume = fmt.Sprintf(
-				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
-				c.SimpleVolumePa.Color,
-				c.SimpleVolumePa.Background,
-				c.SimpleVolumePa.SymbolFont,
-				c.SimpleVolumePa.SymbolFontSize,
-				c.SimpleVolumePa.Symbol,
-			)
+		return outputs{list, active}, err
+	})
 
-			c.Values.SoundVolume += fmt.Sprintf(
-				"<span color='%s' background='%s' font='%s' size='%s'>:%d%%</span>",
-				c.SimpleVolumePa.Color,
-				c.SimpleVolumePa.Background,
-				c.SimpleVolumePa.Font,
-				c.SimpleVolumePa.FontSize,
-				int64(vol*100),
-			)
+	return o.list, o.active, err
+}
 
-			c.Channels.UpdateReady <- true
+// RenderVolume returns pango-formatted volume block text. Symbol depends on volume level, muted state has its own
+// symbol and color. Output name is shown after volume if it is not empty.
+func (c *MyConfig) RenderVolume(vol float32, muted bool, output string) string {
+	var (
+		symbol = c.SimpleVolumePa.Symbol
+		color  = c.SimpleVolumePa.Color
+	)
+
+	switch {
+	case muted:
+		symbol = c.SimpleVolumePa.MutedSymbol
+		color = c.SimpleVolumePa.MutedColor
+
+	case len(c.SimpleVolumePa.LevelSymbols) > 0:
+		n := len(c.SimpleVolumePa.LevelSymbols)
+		level := int(vol * float32(n))
+
+		if level >= n {
+			level = n - 1
 		}
 
-		if err := c.PaReinit(); err != nil {
-			log.Print(err)
+		if level < 0 {
+			level = 0
+		}
 
-			return
+		symbol = c.SimpleVolumePa.LevelSymbols[level]
+	}
+
+	str := fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+		color,
+		c.SimpleVolumePa.Background,
+		c.SimpleVolumePa.SymbolFont,
+		c.SimpleVolumePa.SymbolFontSize,
+		symbol,
+	)
+
+	str += fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>:%d%%</span>",
+		color,
+		c.SimpleVolumePa.Background,
+		c.SimpleVolumePa.Font,
+		c.SimpleVolumePa.FontSize,
+		// Same rounding as in pavucontrol.
+		int64(math.Round(float64(vol)*100)),
+	)
+
+	if output != "" {
+		str += fmt.Sprintf(
+			"<span color='%s' background='%s' font='%s' size='%s'> %s</span>",
+			color,
+			c.SimpleVolumePa.Background,
+			c.SimpleVolumePa.Font,
+			c.SimpleVolumePa.FontSize,
+			html.EscapeString(output),
+		)
+	}
+
+	return str
+}
+
+// OutputName returns short name of given output: alias from config, if any, or port description.
+func (c *MyConfig) OutputName(o p.Output) string {
+	for _, name := range []string{o.PortName, o.CardName, o.PortID, o.CardID} {
+		if alias, exist := c.SimpleVolumePa.OutputAliases[name]; exist {
+			return alias
 		}
 	}
 
-	// This code is unreachable :(
-	c.Values.PA.Close() //nolint:govet
+	return o.PortName
 }
 
-// PaReinit re-inits pulseaudio and connection to it.
-func (c *MyConfig) PaReinit() error {
-	var err error
+// CycleOutput activates next (dir > 0) or previous (dir < 0) available output.
+func (c *MyConfig) CycleOutput(dir int) {
+	outputs, active, err := c.PaOutputs()
+
+	if err != nil {
+		log.Printf("Unable to get list of pulseaudio outputs: %s", err)
+
+		return
+	}
 
-	// If we out of updates, seems someone killed pulseaudio server. Restart it.
+	n := len(outputs)
+
+	for i := (active + dir + n) % n; i != active; i = (i + dir + n) % n {
+		// Unplugged ports and fake "None" output are not available.
+		if !outputs[i].Available {
+			continue
+		}
+
+		o := outputs[i]
+
+		if _, err := PaCall(c, func(*p.Client) (struct{}, error) { return struct{}{}, o.Activate() }); err != nil {
+			log.Printf("Unable to activate pulseaudio output %s: %s", outputs[i].PortName, err)
+		}
+
+		return
+	}
+}
+
+// PaRestartServer kills pulseaudio server if it is running but not responding and starts new one. It works only with
+// pulseaudio daemon itself, not with pipewire-pulse.
+func (c *MyConfig) PaRestartServer() error {
 	cmd := exec.Command("pulseaudio", "--check")
 
 	if err := cmd.Run(); err == nil {
@@ -164,59 +433,137 @@ func (c *MyConfig) PaReinit() error {
 		return fmt.Errorf("unable to initialize pulseaudio server instance: %w", err)
 	}
 
-	c.Values.PA, err = p.NewClient()
-
-	if err != nil {
-		return fmt.Errorf("unable to make client connection to pulseaudio: %w", err)
-	}
-
-	return err
+	return nil
 }
 
 func (c *MyConfig) SVPAHandler() {
 	for e := range c.Channels.SVPAHandlerChan {
+		if e.Button == c.SimpleVolumePa.CycleOutputButton {
+			c.CycleOutput(1)
+
+			continue
+		}
+
+		if c.SimpleVolumePa.CycleOutputModifier != "" && slices.Contains(e.Modifiers, c.SimpleVolumePa.CycleOutputModifier) {
+			switch e.Button {
+			case c.SimpleVolumePa.WheelUp:
+				c.CycleOutput(-1)
+
+				continue
+
+			case c.SimpleVolumePa.WheelDown:
+				c.CycleOutput(1)
+
+				continue
+			}
+		}
+
+		if e.Button == c.SimpleVolumePa.MuteButton {
+			if _, err := PaCall(c, func(pa *p.Client) (bool, error) { return pa.ToggleMute() }); err != nil {
+				log.Printf("Unable to toggle pulseaudio mute: %s", err)
+			}
+
+			continue
+		}
+
 		if e.Button == 3 {
 			c.Channels.RunChan <- c.SimpleVolumePa.RightClickCmd
 
 			continue
 		}
 
-		vol, err := c.Values.PA.Volume()
+		vol, err := PaCall(c, func(pa *p.Client) (float32, error) { return pa.Volume() })
 
+		// UpdateVolumeInfo takes care of reconnection.
 		if err != nil {
-			if err := c.PaReinit(); err != nil {
-				log.Printf("Unable to get pulseaudio volume: %s", err)
-			} else {
-				vol, err = c.Values.PA.Volume()
+			log.Printf("Unable to get pulseaudio volume: %s", err)
 
-				if err != nil {
-					log.Printf("Unable to get volume pulseaudio server behaves weirdly: %s", err)
-				}
-			}
+			continue
+		}
+
+		step := c.SimpleVolumePa.Step
+
+		if slices.Contains(e.Modifiers, c.SimpleVolumePa.FineStepModifier) {
+			step = c.SimpleVolumePa.FineStep
 		}
 
 		switch e.Button {
 		case c.SimpleVolumePa.WheelUp:
-			vol += float32(c.SimpleVolumePa.Step) / 100
+			limit := c.VolumeLimit()
 
-			if vol > (float32(c.SimpleVolumePa.MaxVolumeLimit) / 100) {
-				vol = float32(c.SimpleVolumePa.MaxVolumeLimit) / 100
+			// Volume can be above limit, e.g. after switch to sink with lower limit. Scrolling up must not drop it.
+			if vol >= limit {
+				continue
 			}
 
-			if err := c.Values.PA.SetVolume(vol); err != nil {
-				log.Printf("Unable to set pulseaudio volume: %s", err)
-			}
+			vol = min(c.StepVolume(vol, step), limit)
 
 		case c.SimpleVolumePa.WheelDown:
-			vol -= float32(c.SimpleVolumePa.Step) / 100
+			vol = max(c.StepVolume(vol, -step), 0)
 
-			if vol < 0 {
-				vol = 0
-			}
+		default:
+			continue
+		}
 
-			if err := c.Values.PA.SetVolume(vol); err != nil {
-				log.Printf("Unable to set pulseaudio volume: %s", err)
+		if err := c.SetVolume(vol); err != nil {
+			log.Printf("Unable to set pulseaudio volume: %s", err)
+		}
+	}
+}
+
+// paMinDB is gain below which db volume scale steps down to silence.
+const paMinDB = -60
+
+// StepVolume returns volume changed by given step. Vol is sink volume as pulseaudio reports it, 1 is 100%. It is
+// already cubic: amplitude is its cube, this is what pavucontrol shows. With linear scale step is in percents of that
+// volume. With db scale step is in dB of gain, which is 60*log10(vol), so steps are finer at low volume and coarser
+// at high volume.
+func (c *MyConfig) StepVolume(vol float32, step int) float32 {
+	if c.SimpleVolumePa.VolumeScale != "db" {
+		return vol + float32(step)/100
+	}
+
+	db := float64(paMinDB)
+
+	if vol > 0 {
+		db = max(60*math.Log10(float64(vol)), paMinDB)
+	}
+
+	db += float64(step)
+
+	if db <= paMinDB {
+		return 0
+	}
+
+	return float32(math.Pow(10, db/60))
+}
+
+// VolumeLimit returns max volume of default sink: limit of first matching sink_limits entry or max_volume_limit.
+func (c *MyConfig) VolumeLimit() float32 {
+	limit := c.SimpleVolumePa.MaxVolumeLimit
+
+	if len(c.SimpleVolumePa.SinkLimits) > 0 {
+		server, err := PaCall(c, func(pa *p.Client) (*p.Server, error) { return pa.ServerInfo() })
+
+		if err != nil {
+			log.Printf("Unable to get pulseaudio server info: %s", err)
+		} else {
+			for _, l := range c.SimpleVolumePa.SinkLimits {
+				if l.SinkRe.MatchString(server.DefaultSink) {
+					limit = l.Limit
+
+					break
+				}
 			}
 		}
 	}
+
+	return float32(limit) / 100
+}
+
+// SetVolume sets volume of default sink.
+func (c *MyConfig) SetVolume(vol float32) error {
+	_, err := PaCall(c, func(pa *p.Client) (struct{}, error) { return struct{}{}, pa.SetVolume(vol) })
+
+	return err
 }
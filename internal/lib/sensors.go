@@ -0,0 +1,181 @@
+package lib
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+)
+
+// UpdateSensors reads configured hwmon sensors: temperatures, fan speeds, voltages, power and current.
+func (c *MyConfig) UpdateSensors() {
+	var (
+		InitialDelay       = 100 * time.Millisecond
+		LoopIterationDelay = time.Duration(c.Sensors.Interval) * time.Second
+		Delay              = InitialDelay
+		ticker             = time.NewTicker(Delay)
+		sensors            = make([][]HwmonSensor, len(c.Sensors.Items))
+		lastScan           = make([]time.Time, len(c.Sensors.Items))
+	)
+
+	for range ticker.C {
+		var (
+			items  []string
+			urgent bool
+		)
+
+		if Delay == InitialDelay {
+			Delay = LoopIterationDelay
+			ticker.Reset(Delay)
+		}
+
+		for i, item := range c.Sensors.Items {
+			// Hwmon chips can appear late, e.g. when gpu driver is loaded, so search again if nothing found.
+			if len(sensors[i]) == 0 && time.Since(lastScan[i]) >= time.Minute {
+				sensors[i] = c.FindSensors(i)
+				lastScan[i] = time.Now()
+			}
+
+			value, ok := c.ReadSensors(item.Kind, item.Aggregate, sensors[i])
+
+			if !ok {
+				sensors[i] = nil
+
+				continue
+			}
+
+			color := c.Sensors.Color
+
+			switch {
+			case item.Critical != 0 && value >= item.Critical:
+				color = c.Sensors.CriticalColor
+				urgent = true
+			case item.Warning != 0 && value >= item.Warning:
+				color = c.Sensors.WarningColor
+			}
+
+			text := c.FormatSensor(item.Kind, value)
+
+			if item.Label != "" {
+				text = item.Label + " " + text
+			}
+
+			items = append(items, fmt.Sprintf(
+				"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+				color,
+				c.Sensors.Background,
+				c.Sensors.Font,
+				c.Sensors.FontSize,
+				html.EscapeString(text),
+			))
+		}
+
+		str := strings.Join(items, fmt.Sprintf(
+			"<span background='%s' font='%s' size='%s'> </span>",
+			c.Sensors.Background,
+			c.Sensors.Font,
+			c.Sensors.FontSize,
+		))
+
+		if c.Values.Sensors != str || c.Values.SensorsUrgent != urgent {
+			c.Values.Sensors = str
+			c.Values.SensorsUrgent = urgent
+			c.Channels.UpdateReady <- true
+		}
+	}
+}
+
+// FindSensors returns hwmon inputs of i-th configured sensor.
+func (c *MyConfig) FindSensors(i int) []HwmonSensor {
+	item := c.Sensors.Items[i]
+
+	sensors, err := FindHwmonSensors(c.Sensors.HwmonRoot, item.Kind, item.Chip, item.Sensors)
+
+	if err != nil {
+		log.Printf("Unable to search hwmon sensors in %s: %s", c.Sensors.HwmonRoot, err)
+	}
+
+	if len(sensors) == 0 {
+		log.Printf("No %s sensors of chips %v with labels %v found", item.Kind, item.Chip, item.Sensors)
+	}
+
+	return sensors
+}
+
+// ReadSensors returns average or maximum of given sensors converted to displayed units. Sensors that can not be read
+// are skipped, false is returned if none could be read.
+func (c *MyConfig) ReadSensors(kind, aggregate string, sensors []HwmonSensor) (float64, bool) {
+	var (
+		sum   float64
+		maxV  float64
+		count int
+	)
+
+	for _, s := range sensors {
+		raw, err := ReadHwmonValue(s.File)
+
+		if err != nil {
+			log.Printf("Unable to read sensor %s: %s", s.File, err)
+
+			continue
+		}
+
+		value := c.ConvertSensor(kind, raw)
+
+		if count == 0 || value > maxV {
+			maxV = value
+		}
+
+		sum += value
+		count++
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	if aggregate == "avg" {
+		return sum / float64(count), true
+	}
+
+	return maxV, true
+}
+
+// ConvertSensor converts raw hwmon value to displayed units: degrees of configured scale, RPM, V, W or A.
+func (c *MyConfig) ConvertSensor(kind string, raw int64) float64 {
+	switch kind {
+	case "temp":
+		celsius := float64(raw) / 1000
+
+		if c.Sensors.Unit == "F" {
+			return celsius*9/5 + 32
+		}
+
+		return celsius
+	case "in", "curr":
+		return float64(raw) / 1000
+	case "power":
+		return float64(raw) / 1e6
+	default:
+		return float64(raw)
+	}
+}
+
+// FormatSensor formats value in displayed units.
+func (c *MyConfig) FormatSensor(kind string, value float64) string {
+	switch kind {
+	case "temp":
+		return fmt.Sprintf("%.0f°%s", value, c.Sensors.Unit)
+	case "fan":
+		return fmt.Sprintf("%.0frpm", value)
+	case "in":
+		return fmt.Sprintf("%.2fV", value)
+	case "curr":
+		return fmt.Sprintf("%.2fA", value)
+	case "power":
+		return fmt.Sprintf("%.1fW", value)
+	default:
+		return fmt.Sprintf("%.0f", value)
+	}
+}
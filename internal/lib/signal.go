@@ -25,6 +25,13 @@ func (c *MyConfig) SigHandler() {
 			c.Channels.UpdateReady <- true
 
 		case syscall.SIGQUIT, syscall.SIGTERM, syscall.SIGINT:
+			// Do not lose focused time tracked since last periodic save.
+			if c.FocusTime.Enabled {
+				if err := FT.Save(c.FocusTime.KeepDays); err != nil {
+					log.Printf("Unable to save focus time: %s", err)
+				}
+			}
+
 			os.Exit(0)
 
 		// We have signal that we're not interested in, so make a new loop iteration.
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"bytes"
+	"fmt"
 	"log"
 	"os"
 	"os/exec"
@@ -48,6 +49,63 @@ func (c *MyConfig) Spawner() {
 	}
 }
 
+// RunApp launches command of app button with given index. By default it is forked by Spawner(), but if app.I3Exec is
+// set, program is launched via i3 exec ipc command, so i3 can track its startup and put its window to given workspace.
+func (c *MyConfig) RunApp(num int) {
+	app := c.Apps[num]
+	prg := append([]string{}, app.Cmd)
+	prg = append(prg, app.Args...)
+
+	if !app.I3Exec {
+		c.Channels.RunChan <- prg
+
+		return
+	}
+
+	if err := I3Spawn(prg, app.Workspace, app.StartupID); err != nil {
+		log.Printf("Unable to spawn %s via i3, falling back to fork: %s", app.Cmd, err)
+
+		c.Channels.RunChan <- prg
+	}
+}
+
+// I3Spawn asks i3 to run given program via exec command. If workspace is not empty, i3 switches to it first, so new
+// window appears there. If startupID is false, program is run with --no-startup-id.
+func I3Spawn(prg []string, workspace string, startupID bool) error {
+	var command string
+
+	if workspace != "" {
+		command = fmt.Sprintf("workspace --no-auto-back-and-forth %s; ", I3Quote(workspace))
+	}
+
+	command += "exec "
+
+	if !startupID {
+		command += "--no-startup-id "
+	}
+
+	command += I3Quote(ShellJoin(prg))
+
+	return RunI3Command(command)
+}
+
+// ShellJoin joins command and its arguments to string suitable for /bin/sh -c, each element is single-quoted if needed.
+func ShellJoin(prg []string) string {
+	quoted := make([]string, 0, len(prg))
+
+	for _, arg := range prg {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%") == "" {
+			quoted = append(quoted, arg)
+
+			continue
+		}
+
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
+
 // CleanZombies reaps processes spawned by Spawner() and already exited.
 func (c *MyConfig) CleanZombies() {
 	r := syscall.Rusage{}
@@ -101,32 +101,102 @@ func (c *MyConfig) ParseStdin() {
 			continue
 		}
 
+		// Any click on binding mode block returns i3 to default mode.
+		if e.Name == "binding-mode" {
+			if c.BindingMode.Enabled {
+				if err := RunI3Command("mode default"); err != nil {
+					log.Print(err)
+				}
+			}
+
+			continue
+		}
+
+		if e.Name == "layout" {
+			if c.Layout.Enabled {
+				go c.LayoutHandler(e)
+			}
+
+			continue
+		}
+
+		if e.Name == "focus-time" {
+			if c.FocusTime.Enabled {
+				c.FocusTimeReport()
+			}
+
+			continue
+		}
+
+		if e.Name == "mic-volume-pa" {
+			if c.MicVolumePa.Enabled {
+				c.Channels.MicHandlerChan <- e
+			}
+
+			continue
+		}
+
+		if e.Name == "stream-volume-pa" {
+			if c.StreamVolumePa.Enabled {
+				c.Channels.StreamHandlerChan <- e
+			}
+
+			continue
+		}
+
+		if e.Name == "card-profile" {
+			if c.CardProfile.Enabled {
+				go c.CardProfileHandler(e)
+			}
+
+			continue
+		}
+
+		if e.Name == "battery" {
+			if c.Battery.Enabled && e.Button == c.Battery.ChargeLimit.Button && len(c.Battery.ChargeLimit.Presets) > 0 {
+				go c.CycleChargeLimit()
+			} else if c.Battery.Enabled && c.Battery.Aggregate {
+				c.Values.BatteryShowAll = !c.Values.BatteryShowAll
+				c.Channels.UpdateReady <- true
+			}
+
+			continue
+		}
+
+		if e.Name == "cpu-usage" {
+			if c.CPUUsage.Enabled {
+				c.Values.CPUUsagePerCore = !c.Values.CPUUsagePerCore
+				c.Channels.UpdateReady <- true
+			}
+
+			continue
+		}
+
+		if e.Name == "cpu-freq" {
+			if c.CPUFreq.Enabled {
+				go c.CPUFreqHandler(e)
+			}
+
+			continue
+		}
+
 		if !c.AppButtons.Enabled {
 			continue
 		}
 
-		for _, app := range c.Apps {
+		for num, app := range c.Apps {
 			switch {
 			case app.Name != "" && app.Instance != "":
 				if app.Name == e.Name && app.Instance == e.Instance {
-					prg := append([]string{}, app.Cmd)
-					prg = append(prg, app.Args...)
-
-					c.Channels.RunChan <- prg
+					c.RunApp(num)
 
 					break
 				}
 			case app.Name != "" && e.Name == app.Name:
-				prg := append([]string{}, app.Cmd)
-				prg = append(prg, app.Args...)
-
-				c.Channels.RunChan <- prg
+				c.RunApp(num)
 
 			case app.Instance != "" && e.Instance == app.Instance:
-				prg := append([]string{}, app.Cmd)
-				prg = append(prg, app.Args...)
-
-				c.Channels.RunChan <- prg
+				c.RunApp(num)
 			}
 		}
 	}
@@ -0,0 +1,215 @@
+package lib
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+)
+
+// selectedStream is index of sink input shown on stream volume block.
+var selectedStream struct {
+	mu    sync.Mutex
+	index int
+}
+
+// UpdateStreamVolume tracks playback streams (sink inputs) and volume of one selected stream.
+func (c *MyConfig) UpdateStreamVolume() {
+	PactlSubscribe([]string{"sink-input", "server"}, c.RefreshStreamVolume, c.StreamUnavailable)
+}
+
+// Streams returns list of playback streams shown on stream volume block.
+func (c *MyConfig) Streams() ([]PactlStream, error) {
+	var (
+		inputs  []PactlStream
+		streams []PactlStream
+	)
+
+	if err := PactlList("sink-inputs", &inputs); err != nil {
+		return nil, err
+	}
+
+	for _, s := range inputs {
+		if PactlIgnored(s, c.StreamVolumePa.IgnoreApps) || (s.Corked && !c.StreamVolumePa.ShowPaused) {
+			continue
+		}
+
+		streams = append(streams, s)
+	}
+
+	return streams, nil
+}
+
+// SelectedStream returns position of selected stream in given list. If selected stream has gone, first one is
+// selected. If list is empty, -1 is returned.
+func SelectedStream(streams []PactlStream) int {
+	selectedStream.mu.Lock()
+	defer selectedStream.mu.Unlock()
+
+	if len(streams) == 0 {
+		return -1
+	}
+
+	for i, s := range streams {
+		if s.Index == selectedStream.index {
+			return i
+		}
+	}
+
+	selectedStream.index = streams[0].Index
+
+	return 0
+}
+
+// RefreshStreamVolume gets list of playback streams and renders selected one for i3bar.
+func (c *MyConfig) RefreshStreamVolume() error {
+	streams, err := c.Streams()
+
+	if err != nil {
+		return fmt.Errorf("unable to get pulseaudio sink inputs: %w", err)
+	}
+
+	c.SetStreamVolume(c.RenderStreamVolume(streams, SelectedStream(streams)))
+
+	return nil
+}
+
+// StreamUnavailable hides stream volume block when streams can not be read.
+func (c *MyConfig) StreamUnavailable() {
+	c.SetStreamVolume("")
+}
+
+// SetStreamVolume sets text of stream volume block and asks for bar update if it has changed.
+func (c *MyConfig) SetStreamVolume(str string) {
+	if c.Values.StreamVolume != str {
+		c.Values.StreamVolume = str
+		c.Channels.UpdateReady <- true
+	}
+}
+
+// RenderStreamVolume returns pango-formatted text with application name and volume of selected stream. Empty string
+// is returned if there are no streams and block must be hidden.
+func (c *MyConfig) RenderStreamVolume(streams []PactlStream, pos int) string {
+	if pos < 0 {
+		if c.StreamVolumePa.HideIdle {
+			return ""
+		}
+
+		return fmt.Sprintf(
+			"<span color='%s' background='%s' font='%s' size='%s'>%s</span>",
+			c.StreamVolumePa.MutedColor,
+			c.StreamVolumePa.Background,
+			c.StreamVolumePa.Font,
+			c.StreamVolumePa.FontSize,
+			c.StreamVolumePa.Symbol,
+		)
+	}
+
+	var (
+		s     = streams[pos]
+		color = c.StreamVolumePa.Color
+		name  = strings.TrimSpace(PactlAppName(s))
+	)
+
+	if s.Mute {
+		color = c.StreamVolumePa.MutedColor
+	}
+
+	// Count in runes, not in bytes, otherwise we can cut multibyte symbol in half.
+	if r := []rune(name); len(r) > c.StreamVolumePa.MaxNameLength {
+		name = strings.TrimSpace(string(r[:c.StreamVolumePa.MaxNameLength])) + "…"
+	}
+
+	str := fmt.Sprintf(
+		"<span color='%s' background='%s' font='%s' size='%s'>%s %s:%d%%</span>",
+		color,
+		c.StreamVolumePa.Background,
+		c.StreamVolumePa.Font,
+		c.StreamVolumePa.FontSize,
+		c.StreamVolumePa.Symbol,
+		html.EscapeString(name),
+		int64(PactlAvgVolume(s.Volume)*100+0.5),
+	)
+
+	if len(streams) > 1 {
+		str += fmt.Sprintf(
+			"<span color='%s' background='%s' font='%s' size='%s'> %d/%d</span>",
+			color,
+			c.StreamVolumePa.Background,
+			c.StreamVolumePa.Font,
+			c.StreamVolumePa.FontSize,
+			pos+1,
+			len(streams),
+		)
+	}
+
+	return str
+}
+
+// StreamHandler changes volume of selected stream by mouse wheel, selects next stream and toggles mute by click.
+func (c *MyConfig) StreamHandler() {
+	for e := range c.Channels.StreamHandlerChan {
+		streams, err := c.Streams()
+
+		if err != nil {
+			log.Printf("Unable to get pulseaudio sink inputs: %s", err)
+
+			continue
+		}
+
+		pos := SelectedStream(streams)
+
+		if pos < 0 {
+			continue
+		}
+
+		var (
+			s    = streams[pos]
+			args []string
+		)
+
+		switch e.Button {
+		case c.StreamVolumePa.NextButton:
+			selectedStream.mu.Lock()
+			selectedStream.index = streams[(pos+1)%len(streams)].Index
+			selectedStream.mu.Unlock()
+
+			// Selection change produces no pulseaudio event, so update block by ourselves.
+			if err := c.RefreshStreamVolume(); err != nil {
+				log.Printf("Unable to update stream volume block: %s", err)
+			}
+
+			continue
+
+		case c.StreamVolumePa.MuteButton:
+			args = []string{"set-sink-input-mute", fmt.Sprint(s.Index), "toggle"}
+
+		case c.StreamVolumePa.WheelUp:
+			vol := int(PactlAvgVolume(s.Volume)*100+0.5) + c.StreamVolumePa.Step
+
+			if vol > c.StreamVolumePa.MaxVolumeLimit {
+				vol = c.StreamVolumePa.MaxVolumeLimit
+			}
+
+			args = []string{"set-sink-input-volume", fmt.Sprint(s.Index), fmt.Sprintf("%d%%", vol)}
+
+		case c.StreamVolumePa.WheelDown:
+			vol := int(PactlAvgVolume(s.Volume)*100+0.5) - c.StreamVolumePa.Step
+
+			if vol < 0 {
+				vol = 0
+			}
+
+			args = []string{"set-sink-input-volume", fmt.Sprint(s.Index), fmt.Sprintf("%d%%", vol)}
+
+		default:
+			continue
+		}
+
+		// Block itself is updated by pactl subscribe events.
+		if _, err := Pactl(args...); err != nil {
+			log.Print(err)
+		}
+	}
+}
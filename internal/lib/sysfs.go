@@ -0,0 +1,95 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadSysfsString reads given file of sysfs dir and returns its content without trailing newline.
+func ReadSysfsString(dir, name string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// ReadSysfsFloat reads number from given file of sysfs dir.
+func ReadSysfsFloat(dir, name string) (float64, error) {
+	s, err := ReadSysfsString(dir, name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+// SysfsWrite is value to be written to sysfs file.
+type SysfsWrite struct {
+	File  string
+	Value string
+}
+
+// sysfsHelperScript writes value to file for each "file value" pair of arguments, stopping on first failure.
+const sysfsHelperScript = `while [ $# -gt 1 ]; do printf '%s' "$2" > "$1" || exit 1; shift 2; done`
+
+// WriteSysfsAll writes values to sysfs files in given order. If we have no permission and helper command is set, the
+// rest of writes is done by single helper run, so user is asked for password only once. Helper is privilege
+// escalation command, e.g. "pkexec" or "sudo -n", it is run with sh script and "file value" pairs as arguments.
+func WriteSysfsAll(writes []SysfsWrite, helper []string) error {
+	for i, w := range writes {
+		err := os.WriteFile(w.File, []byte(w.Value), 0644) //nolint: gosec
+
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, fs.ErrPermission) || len(helper) == 0 {
+			return err
+		}
+
+		return runSysfsHelper(writes[i:], helper)
+	}
+
+	return nil
+}
+
+// runSysfsHelper writes values to sysfs files via helper command.
+func runSysfsHelper(writes []SysfsWrite, helper []string) error {
+	var files []string
+
+	// Helper can ask for password, so give user some time.
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	args := append(append([]string{}, helper[1:]...), "sh", "-c", sysfsHelperScript, "sh")
+
+	for _, w := range writes {
+		args = append(args, w.File, w.Value)
+		files = append(files, w.File)
+	}
+
+	cmd := exec.CommandContext(ctx, helper[0], args...) //nolint: gosec
+	cmd.Dir = "/"
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf(
+			"unable to write %s via %s: %w: %s",
+			strings.Join(files, " "), strings.Join(helper, " "), err, bytes.TrimSpace(out),
+		)
+	}
+
+	return nil
+}
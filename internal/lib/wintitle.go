@@ -0,0 +1,49 @@
+package lib
+
+import (
+	"html"
+	"strings"
+
+	"go.i3wm.org/i3"
+)
+
+// UpdateWindowTitle updates title of focused window shown on i3bar.
+func (c *MyConfig) UpdateWindowTitle(n *i3.Node) {
+	var title string
+
+	if n != nil {
+		title = c.FormatWindowTitle(n.WindowProperties.Class, n.Name)
+	}
+
+	if c.Values.WindowTitle != title {
+		c.Values.WindowTitle = title
+		c.Channels.UpdateReady <- true
+	}
+}
+
+// FormatWindowTitle applies privacy patterns, rewrite rules and length limit to given window title and returns it
+// escaped for pango markup.
+func (c *MyConfig) FormatWindowTitle(class string, title string) string {
+	for _, re := range c.WindowTitle.PrivacyRe {
+		if re.MatchString(title) {
+			return html.EscapeString(c.WindowTitle.PrivacyText)
+		}
+	}
+
+	for _, rule := range c.WindowTitle.Rewrite {
+		if rule.ClassRe != nil && !rule.ClassRe.MatchString(class) {
+			continue
+		}
+
+		title = rule.MatchRe.ReplaceAllString(title, rule.Replace)
+	}
+
+	title = strings.TrimSpace(title)
+
+	// Count in runes, not in bytes, otherwise we can cut multibyte symbol in half.
+	if r := []rune(title); len(r) > c.WindowTitle.MaxLength {
+		title = strings.TrimSpace(string(r[:c.WindowTitle.MaxLength])) + c.WindowTitle.Ellipsis
+	}
+
+	return html.EscapeString(title)
+}